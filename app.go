@@ -12,6 +12,8 @@ import (
 
 	"VibeCraft/pkg/autoupdater"
 	"VibeCraft/pkg/ffmpeg"
+	"VibeCraft/pkg/manifest"
+	"VibeCraft/pkg/safepath"
 
 	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -22,11 +24,31 @@ type App struct {
 	ctx     context.Context
 	updater *autoupdater.Updater
 	ffmpeg  *ffmpeg.FFmpeg
+	jobs    *jobRegistry
+	render  *frameRender
+	uploads *uploadRegistry
+
+	generatorIndex *generatorIndex
+	watcher        *generatorWatcher
 }
 
 type GeneratorInfo struct {
-	Name     string `json:"name"`
-	Filename string `json:"filename"`
+	Name        string                 `json:"name"`
+	Filename    string                 `json:"filename"`
+	Version     string                 `json:"version"`
+	PackageID   string                 `json:"packageId"`
+	APIVersion  string                 `json:"apiVersion"`
+	Author      string                 `json:"author"`
+	Description string                 `json:"description"`
+	Config      []manifest.ConfigParam `json:"config"`
+	// ParseError est renseigné quand le manifest n'a pas pu être lu : le
+	// générateur reste listé pour pouvoir être supprimé.
+	ParseError string `json:"parseError,omitempty"`
+	// Bundle indique un générateur installé depuis un .vcgen, dans son
+	// propre dossier.
+	Bundle bool `json:"bundle"`
+	// Linked indique un dossier de développement lu en place.
+	Linked bool `json:"linked"`
 }
 
 type ChangelogResult struct {
@@ -41,6 +63,11 @@ func NewApp() *App {
 	return &App{
 		updater: autoupdater.NewUpdater(AppVersion, githubRepo),
 		ffmpeg:  ffmpeg.NewFFmpeg(),
+		jobs:    newJobRegistry(),
+		render:  &frameRender{},
+		uploads: newUploadRegistry(),
+
+		generatorIndex: newGeneratorIndex(),
 	}
 }
 
@@ -48,6 +75,16 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	generatorsDir := a.getGeneratorsDir()
 	os.MkdirAll(generatorsDir, 0755)
+
+	a.ffmpeg.SetCustomPath(a.GetSettings().FFmpegPath)
+	a.sweepTempDir()
+	a.startGeneratorWatcher()
+}
+
+func (a *App) shutdown(ctx context.Context) {
+	if a.watcher != nil {
+		a.watcher.close()
+	}
 }
 
 func (a *App) getGeneratorsDir() string {
@@ -56,14 +93,19 @@ func (a *App) getGeneratorsDir() string {
 }
 
 func (a *App) SaveGenerator(filename string, content string) error {
-	generatorsDir := a.getGeneratorsDir()
-	filePath := filepath.Join(generatorsDir, filename)
+	filePath, err := a.resolveGeneratorPath(filename)
+	if err != nil {
+		return err
+	}
+	defer a.invalidateGenerator(filename)
 	return os.WriteFile(filePath, []byte(content), 0644)
 }
 
 func (a *App) LoadGenerator(filename string) (string, error) {
-	generatorsDir := a.getGeneratorsDir()
-	filePath := filepath.Join(generatorsDir, filename)
+	filePath, err := a.resolveGeneratorPath(filename)
+	if err != nil {
+		return "", err
+	}
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return "", err
@@ -94,32 +136,111 @@ func (a *App) ListGenerators() ([]GeneratorInfo, error) {
 		return generators, nil
 	}
 
+	a.generatorIndex.mu.Lock()
+	defer a.generatorIndex.mu.Unlock()
+
+	indexPath := a.getGeneratorIndexPath()
+	a.generatorIndex.load(indexPath)
+
+	present := make(map[string]bool)
 	for _, file := range files {
-		if filepath.Ext(file.Name()) == ".js" {
-			content, err := a.LoadGenerator(file.Name())
-			generatorName := file.Name()
-			if err == nil {
-				extractedName := a.extractGeneratorName(content)
-				if extractedName != "Générateur inconnu" {
-					generatorName = extractedName
-				} else {
-					generatorName = strings.TrimSuffix(file.Name(), ".js")
-				}
+		name := file.Name()
+		var key string
+		var read func() GeneratorInfo
+		switch {
+		case file.IsDir() && !strings.HasPrefix(name, "."):
+			// Les bundles sont indexés par leur manifest.json
+			key = name + "/" + bundleManifestName
+			read = func() GeneratorInfo { return readBundleInfo(filepath.Join(generatorsDir, name), name, false) }
+		case filepath.Ext(name) == ".js":
+			key = name
+			read = func() GeneratorInfo { return a.readGeneratorInfo(name) }
+		default:
+			continue
+		}
+
+		stat, err := os.Stat(filepath.Join(generatorsDir, filepath.FromSlash(key)))
+		if err != nil {
+			if !file.IsDir() {
+				generators = append(generators, read())
 			}
+			continue
+		}
+		present[key] = true
 
-			generators = append(generators, GeneratorInfo{
-				Name:     generatorName,
-				Filename: file.Name(),
-			})
+		info, ok := a.generatorIndex.lookup(key, stat)
+		if !ok {
+			info = read()
+			a.generatorIndex.store(key, stat, info)
 		}
+		generators = append(generators, info)
 	}
 
-	return generators, nil
+	a.generatorIndex.prune(present)
+	a.generatorIndex.save(indexPath)
+
+	return append(generators, a.listLinkedGenerators()...), nil
 }
 
+// readGeneratorInfo lit le manifest d'un générateur. En cas d'échec, le nom
+// de la classe (ou du fichier) sert de repli et l'erreur est renvoyée dans
+// ParseError.
+func (a *App) readGeneratorInfo(filename string) GeneratorInfo {
+	info := GeneratorInfo{
+		Name:     strings.TrimSuffix(filename, ".js"),
+		Filename: filename,
+	}
+
+	content, err := a.LoadGenerator(filename)
+	if err != nil {
+		info.ParseError = err.Error()
+		return info
+	}
+
+	m, err := manifest.Extract(content)
+	if err != nil {
+		info.ParseError = err.Error()
+	}
+	if m == nil {
+		if extractedName := a.extractGeneratorName(content); extractedName != "Générateur inconnu" {
+			info.Name = extractedName
+		}
+		return info
+	}
+
+	applyManifest(&info, m)
+	return info
+}
+
+func applyManifest(info *GeneratorInfo, m *manifest.Manifest) {
+	if m.Name != "" {
+		info.Name = m.Name
+	}
+	info.Version = m.Version
+	info.PackageID = m.PackageID
+	info.APIVersion = m.APIVersion
+	info.Author = m.Author
+	info.Description = m.Description
+	info.Config = m.Config
+}
+
+// DeleteGenerator supprime un générateur ; pour un bundle, c'est tout son
+// dossier qui est supprimé. Un dossier lié est seulement délié.
 func (a *App) DeleteGenerator(filename string) error {
-	generatorsDir := a.getGeneratorsDir()
-	filePath := filepath.Join(generatorsDir, filename)
+	packageID, _, _ := strings.Cut(filepath.ToSlash(filename), "/")
+	if linkedDir, ok := a.linkedGenerators()[packageID]; ok {
+		return a.unlinkGenerator(linkedDir)
+	}
+	if dir := a.bundleDir(filename); dir != "" {
+		defer a.invalidateGenerator(filepath.Base(dir) + "/" + bundleManifestName)
+		return os.RemoveAll(dir)
+	}
+
+	filePath, err := safepath.Resolve(a.getGeneratorsDir(), filename)
+	if err != nil {
+		return err
+	}
+	defer a.invalidateGenerator(filename)
 	return os.Remove(filePath)
 }
 
@@ -173,11 +294,17 @@ func (a *App) CheckForUpdates() (*autoupdater.UpdateInfo, error) {
 			LatestVersion:  AppVersion,
 		}, nil
 	}
-	return a.updater.CheckForUpdates()
+	return a.updater.CheckForUpdates(a.ctx)
 }
 
 func (a *App) DownloadUpdate(downloadURL string) (string, error) {
-	return a.updater.DownloadUpdate(downloadURL, func(progress autoupdater.UpdateProgress) {
+	ctx, done, err := a.jobs.start(a.ctx, jobUpdateDownload)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
+	return a.updater.DownloadUpdate(ctx, downloadURL, func(progress autoupdater.UpdateProgress) {
 		if a.ctx != nil {
 			wailsruntime.EventsEmit(a.ctx, "download-progress", progress)
 		}
@@ -293,15 +420,25 @@ func (a *App) GetLatestReleaseInfo() (*autoupdater.UpdateInfo, error) {
 			ReleaseNotes:   "Mode développement - Pas de vérification de mise à jour",
 		}, nil
 	}
-	return a.updater.CheckForUpdates()
+	return a.updater.CheckForUpdates(a.ctx)
 }
 
 func (a *App) IsFFmpegInstalled() bool {
 	return a.ffmpeg.IsInstalled()
 }
 
+func (a *App) GetFFmpegInfo() (*ffmpeg.Info, error) {
+	return a.ffmpeg.GetInfo(a.ctx)
+}
+
 func (a *App) DownloadFFmpeg() error {
-	return a.ffmpeg.Download(func(progress float64) {
+	ctx, done, err := a.jobs.start(a.ctx, jobFFmpegDownload)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	return a.ffmpeg.Download(ctx, func(progress float64) {
 		if a.ctx != nil {
 			wailsruntime.EventsEmit(a.ctx, "ffmpeg-download-progress", map[string]interface{}{
 				"progress": progress,
@@ -310,71 +447,176 @@ func (a *App) DownloadFFmpeg() error {
 	})
 }
 
-func (a *App) ConvertVideoToMP4(webmPath, mp4Path string) error {
-	homeDir, _ := os.UserHomeDir()
-	tempDir := filepath.Join(homeDir, ".vibecraft", "temp")
+func (a *App) GetEncodeProfiles() []ffmpeg.EncodeProfile {
+	return ffmpeg.Profiles()
+}
 
-	webmFullPath := filepath.Join(tempDir, webmPath)
-	mp4FullPath := filepath.Join(tempDir, mp4Path)
+func (a *App) SetDefaultEncodeProfile(profile string) error {
+	settings := a.GetSettings()
+	settings.DefaultProfile = profile
+	return a.SaveSettings(settings)
+}
 
-	// Vérifier que le fichier WebM existe
-	if _, err := os.Stat(webmFullPath); os.IsNotExist(err) {
-		return fmt.Errorf("fichier WebM introuvable: %s", webmFullPath)
+// ConvertVideoToMP4, comme les autres conversions, reçoit la durée de
+// l'enregistrement en secondes (0 si inconnue) : les WebM de MediaRecorder ne
+// l'annoncent pas, et sans elle la progression reste sans pourcentage.
+func (a *App) ConvertVideoToMP4(webmPath, mp4Path, profile string, duration float64) error {
+	if profile == "" {
+		profile = a.GetSettings().DefaultProfile
 	}
-
-	err := a.ffmpeg.ConvertWebMToMP4(webmFullPath, mp4FullPath)
+	opts, err := ffmpeg.GetProfile(profile)
 	if err != nil {
-		return fmt.Errorf("erreur conversion ffmpeg: %w", err)
+		return err
 	}
 
-	// Vérifier que le fichier MP4 a été créé
-	if _, err := os.Stat(mp4FullPath); os.IsNotExist(err) {
-		return fmt.Errorf("fichier MP4 non créé: %s", mp4FullPath)
-	}
+	return a.runConversion(webmPath, mp4Path, duration, opts.EstimateSize, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		return a.ffmpeg.ConvertWebMToMP4(ctx, inputPath, outputPath, opts, duration, progressCallback)
+	})
+}
 
-	return nil
+func (a *App) GetDefaultGIFOptions() ffmpeg.GIFOptions {
+	return ffmpeg.DefaultGIFOptions()
 }
 
-func (a *App) SaveTempFile(filename string, data []int) error {
-	homeDir, _ := os.UserHomeDir()
-	tempDir := filepath.Join(homeDir, ".vibecraft", "temp")
-	os.MkdirAll(tempDir, 0755)
+func (a *App) ConvertVideoToGIF(webmPath, gifPath string, opts ffmpeg.GIFOptions, duration float64) error {
+	return a.runConversion(webmPath, gifPath, duration, opts.EstimateSize, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		return a.ffmpeg.ConvertToGIF(ctx, inputPath, outputPath, opts, duration, progressCallback)
+	})
+}
+
+func (a *App) GetDefaultWebPOptions() ffmpeg.WebPOptions {
+	return ffmpeg.DefaultWebPOptions()
+}
+
+func (a *App) ConvertVideoToWebP(webmPath, webpPath string, opts ffmpeg.WebPOptions, duration float64) error {
+	return a.runConversion(webmPath, webpPath, duration, opts.EstimateSize, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		return a.ffmpeg.ConvertToWebP(ctx, inputPath, outputPath, opts, duration, progressCallback)
+	})
+}
+
+func (a *App) GetDefaultAPNGOptions() ffmpeg.APNGOptions {
+	return ffmpeg.DefaultAPNGOptions()
+}
+
+func (a *App) ConvertVideoToAPNG(webmPath, apngPath string, opts ffmpeg.APNGOptions, duration float64) error {
+	return a.runConversion(webmPath, apngPath, duration, opts.EstimateSize, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		return a.ffmpeg.ConvertToAPNG(ctx, inputPath, outputPath, opts, duration, progressCallback)
+	})
+}
 
-	byteData := make([]byte, len(data))
-	for i, v := range data {
-		byteData[i] = byte(v)
+func (a *App) ConvertVideoWithAlpha(webmPath, outputPath, format string, duration float64) error {
+	estimate := func(info ffmpeg.MediaInfo) int64 {
+		return ffmpeg.EstimateAlphaSize(format, info)
 	}
+	return a.runConversion(webmPath, outputPath, duration, estimate, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		return a.ffmpeg.ConvertWithAlpha(ctx, inputPath, outputPath, format, duration, progressCallback)
+	})
+}
 
-	filePath := filepath.Join(tempDir, filename)
-	return os.WriteFile(filePath, byteData, 0644)
+func (a *App) SelectAudioFile() (string, error) {
+	return wailsruntime.OpenFileDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title: "Choisir une musique",
+		Filters: []wailsruntime.FileFilter{
+			{DisplayName: "Audio (*.mp3, *.wav, *.ogg, *.m4a, *.flac)", Pattern: "*.mp3;*.wav;*.ogg;*.m4a;*.flac;*.aac;*.opus"},
+		},
+	})
 }
 
-func (a *App) ReadTempFile(filename string) ([]int, error) {
-	homeDir, _ := os.UserHomeDir()
-	tempDir := filepath.Join(homeDir, ".vibecraft", "temp")
-	filePath := filepath.Join(tempDir, filename)
+func (a *App) MuxBackgroundMusic(videoPath, outputPath string, track ffmpeg.AudioTrack, duration float64) error {
+	return a.runConversion(videoPath, outputPath, duration, ffmpeg.EstimateRemuxSize, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		return a.ffmpeg.MuxAudio(ctx, inputPath, outputPath, track, duration, progressCallback)
+	})
+}
+
+func (a *App) MixSoundEffects(videoPath, outputPath string, events []ffmpeg.SoundEvent, effects map[string]ffmpeg.SoundEffect, duration float64) error {
+	return a.runConversion(videoPath, outputPath, duration, ffmpeg.EstimateRemuxSize, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		return a.ffmpeg.MixSoundEffects(ctx, inputPath, outputPath, events, effects, duration, progressCallback)
+	})
+}
+
+func (a *App) GetDefaultLoudnessOptions() ffmpeg.LoudnessOptions {
+	return ffmpeg.DefaultLoudnessOptions()
+}
+
+func (a *App) NormalizeLoudness(videoPath, outputPath string, opts ffmpeg.LoudnessOptions) (*ffmpeg.LoudnessReport, error) {
+	var report *ffmpeg.LoudnessReport
+	err := a.runConversion(videoPath, outputPath, 0, ffmpeg.EstimateRemuxSize, func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error {
+		var err error
+		report, err = a.ffmpeg.NormalizeLoudness(ctx, inputPath, outputPath, opts, progressCallback)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// runConversion résout les fichiers dans le dossier temporaire, enregistre la
+// conversion comme tâche annulable (identifiée par le fichier de sortie) et
+// relaie la progression vers le frontend. estimate donne la taille attendue
+// de la sortie d'après les caractéristiques de la source, dont la durée
+// fournie par l'appelant si le fichier ne l'annonce pas.
+func (a *App) runConversion(inputName, outputName string, duration float64, estimate func(ffmpeg.MediaInfo) int64, convert func(ctx context.Context, inputPath, outputPath string, progressCallback func(ffmpeg.ConvertProgress)) error) error {
+	tempDir := getTempDir()
+
+	inputFullPath, err := tempFilePath(inputName)
+	if err != nil {
+		return err
+	}
+	outputFullPath, err := tempFilePath(outputName)
+	if err != nil {
+		return err
+	}
+
+	// Vérifier que le fichier source existe
+	stat, err := os.Stat(inputFullPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("fichier source introuvable: %s", inputFullPath)
+	}
+	if err == nil {
+		// Sans ffprobe, on suppose une sortie de la taille de la source
+		needed := stat.Size()
+		if info, err := a.ffmpeg.Probe(a.ctx, inputFullPath); err == nil {
+			if info.Duration == 0 {
+				info.Duration = duration
+			}
+			if size := estimate(*info); size > 0 {
+				needed = size
+			}
+		}
+		if err := ensureDiskSpace(tempDir, needed); err != nil {
+			return err
+		}
+	}
 
-	data, err := os.ReadFile(filePath)
+	ctx, done, err := a.jobs.start(a.ctx, outputName)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lecture %s: %w", filePath, err)
+		return err
 	}
+	defer done()
 
-	if len(data) == 0 {
-		return nil, fmt.Errorf("fichier vide: %s", filePath)
+	err = convert(ctx, inputFullPath, outputFullPath, func(progress ffmpeg.ConvertProgress) {
+		if a.ctx != nil {
+			wailsruntime.EventsEmit(a.ctx, "ffmpeg-convert-progress", progress)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("erreur conversion ffmpeg: %w", err)
 	}
 
-	// Convertir []byte en []int pour une meilleure compatibilité avec JavaScript
-	intData := make([]int, len(data))
-	for i, b := range data {
-		intData[i] = int(b)
+	// Vérifier que le fichier de sortie a été créé
+	if _, err := os.Stat(outputFullPath); os.IsNotExist(err) {
+		return fmt.Errorf("fichier de sortie non créé: %s", outputFullPath)
 	}
 
-	return intData, nil
+	return nil
 }
 
-func (a *App) DeleteTempFile(filename string) error {
-	homeDir, _ := os.UserHomeDir()
-	tempDir := filepath.Join(homeDir, ".vibecraft", "temp")
-	filePath := filepath.Join(tempDir, filename)
-	return os.Remove(filePath)
+func (a *App) InspectVideo(filename string) (*ffmpeg.MediaInfo, error) {
+	filePath, err := tempFilePath(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.ffmpeg.Probe(a.ctx, filePath)
 }
@@ -0,0 +1,521 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"VibeCraft/pkg/manifest"
+	"VibeCraft/pkg/safepath"
+)
+
+// Un bundle .vcgen est une archive zip contenant manifest.json, le script
+// d'entrée désigné par son champ "entry" et un dossier assets/ facultatif.
+// Il est décompressé dans ~/.vibecraft/generators/<package_id>/.
+const (
+	bundleExtension    = ".vcgen"
+	bundleManifestName = "manifest.json"
+	bundleDefaultEntry = "index.js"
+	bundleAssetsDir    = "assets"
+
+	// Limites contre les archives piégées (zip bombs)
+	maxBundleSize  = 256 << 20
+	maxBundleFiles = 2000
+)
+
+var packageIDRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+type bundleManifest struct {
+	manifest.Manifest
+	Entry string `json:"entry,omitempty"`
+}
+
+func (m *bundleManifest) validate() error {
+	if err := m.Manifest.Validate(); err != nil {
+		return err
+	}
+	if !packageIDRegex.MatchString(m.PackageID) {
+		return fmt.Errorf("package_id invalide pour un bundle: %q", m.PackageID)
+	}
+	if m.Entry == "" {
+		m.Entry = bundleDefaultEntry
+	}
+	if path.IsAbs(m.Entry) || strings.Contains(m.Entry, "\\") || path.Clean(m.Entry) != m.Entry || strings.HasPrefix(m.Entry, "../") {
+		return fmt.Errorf("chemin du script d'entrée invalide: %s", m.Entry)
+	}
+	if path.Ext(m.Entry) != ".js" {
+		return fmt.Errorf("le script d'entrée doit être un fichier .js: %s", m.Entry)
+	}
+	return nil
+}
+
+// bundleDir renvoie le dossier d'un générateur installé en bundle, ou une
+// chaîne vide pour un générateur en fichier unique.
+func (a *App) bundleDir(filename string) string {
+	dir, _, found := strings.Cut(filepath.ToSlash(filename), "/")
+	if !found {
+		return ""
+	}
+	if linkedDir, ok := a.linkedGenerators()[dir]; ok {
+		return linkedDir
+	}
+	dirPath, err := safepath.Resolve(a.getGeneratorsDir(), dir)
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(dirPath, bundleManifestName)); err != nil {
+		return ""
+	}
+	return dirPath
+}
+
+func readBundleManifest(dir string) (*bundleManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture %s: %w", bundleManifestName, err)
+	}
+	var m bundleManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s invalide: %w", bundleManifestName, err)
+	}
+	return &m, m.validate()
+}
+
+// readBundleInfo lit un générateur au format bundle à partir de son
+// manifest.json. prefix est le premier segment de son nom (le dossier
+// d'installation, ou le package_id d'un dossier lié).
+func readBundleInfo(dir, prefix string, linked bool) GeneratorInfo {
+	info := GeneratorInfo{
+		Name:     prefix,
+		Filename: prefix + "/" + bundleDefaultEntry,
+		Bundle:   true,
+		Linked:   linked,
+	}
+
+	m, err := readBundleManifest(dir)
+	if err != nil {
+		info.ParseError = err.Error()
+	}
+	if m != nil {
+		if m.Entry != "" {
+			info.Filename = prefix + "/" + m.Entry
+		}
+		applyManifest(&info, &m.Manifest)
+	}
+	return info
+}
+
+type bundleArchive struct {
+	reader   *zip.ReadCloser
+	manifest *bundleManifest
+	files    []*zip.File
+	size     int64
+}
+
+func openBundle(bundlePath string) (*bundleArchive, error) {
+	reader, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("bundle illisible: %w", err)
+	}
+
+	bundle := &bundleArchive{reader: reader}
+	fail := func(err error) (*bundleArchive, error) {
+		reader.Close()
+		return nil, err
+	}
+
+	var manifestFile *zip.File
+	entries := make(map[string]bool)
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if f.Mode()&fs.ModeSymlink != 0 {
+			return fail(fmt.Errorf("lien symbolique interdit dans le bundle: %s", f.Name))
+		}
+		bundle.files = append(bundle.files, f)
+		bundle.size += int64(f.UncompressedSize64)
+		entries[f.Name] = true
+		if f.Name == bundleManifestName {
+			manifestFile = f
+		}
+	}
+
+	if len(bundle.files) > maxBundleFiles {
+		return fail(fmt.Errorf("bundle trop volumineux: %d fichiers (max %d)", len(bundle.files), maxBundleFiles))
+	}
+	if bundle.size > maxBundleSize {
+		return fail(fmt.Errorf("bundle trop volumineux: %s décompressés (max %s)", formatBytes(uint64(bundle.size)), formatBytes(maxBundleSize)))
+	}
+	if manifestFile == nil {
+		return fail(fmt.Errorf("%s absent du bundle", bundleManifestName))
+	}
+
+	data, err := readZipFile(manifestFile, 1<<20)
+	if err != nil {
+		return fail(err)
+	}
+	var m bundleManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fail(fmt.Errorf("%s invalide: %w", bundleManifestName, err))
+	}
+	if err := m.validate(); err != nil {
+		return fail(err)
+	}
+	if !entries[m.Entry] {
+		return fail(fmt.Errorf("script d'entrée %s absent du bundle", m.Entry))
+	}
+	bundle.manifest = &m
+
+	return bundle, nil
+}
+
+func (b *bundleArchive) Close() error {
+	return b.reader.Close()
+}
+
+// readZipFile lit une entrée en bornant sa taille réelle, l'en-tête zip
+// pouvant annoncer une taille mensongère.
+func readZipFile(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture %s: %w", f.Name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("fichier trop volumineux dans le bundle: %s", f.Name)
+	}
+	return data, nil
+}
+
+// extractTo décompresse le bundle dans dir, chaque chemin étant vérifié pour
+// rester dans ce dossier.
+func (b *bundleArchive) extractTo(dir string) error {
+	remaining := int64(maxBundleSize)
+	for _, f := range b.files {
+		target, err := safepath.Resolve(dir, f.Name)
+		if err != nil {
+			return fmt.Errorf("chemin refusé dans le bundle: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		written, err := extractZipFile(f, target, remaining)
+		if err != nil {
+			return err
+		}
+		remaining -= written
+	}
+	return nil
+}
+
+func extractZipFile(f *zip.File, target string, limit int64) (int64, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, fmt.Errorf("erreur lecture %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return 0, fmt.Errorf("erreur écriture %s: %w", f.Name, err)
+	}
+	written, err := io.Copy(out, io.LimitReader(rc, limit+1))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, fmt.Errorf("erreur écriture %s: %w", f.Name, err)
+	}
+	if written > limit {
+		return written, fmt.Errorf("bundle trop volumineux une fois décompressé (max %s)", formatBytes(maxBundleSize))
+	}
+	return written, nil
+}
+
+// PreviewGeneratorBundle décrit l'effet de l'import d'un bundle .vcgen
+// préalablement envoyé dans le dossier temporaire, sans rien installer.
+func (a *App) PreviewGeneratorBundle(tempName string) (*ImportReport, error) {
+	bundle, err := openTempBundle(tempName)
+	if err != nil {
+		return nil, err
+	}
+	defer bundle.Close()
+	return a.planBundleImport(bundle)
+}
+
+func openTempBundle(tempName string) (*bundleArchive, error) {
+	bundlePath, err := tempFilePath(tempName)
+	if err != nil {
+		return nil, err
+	}
+	return openBundle(bundlePath)
+}
+
+func (a *App) planBundleImport(bundle *bundleArchive) (*ImportReport, error) {
+	m := bundle.manifest
+
+	// Le manifest du script doit désigner le même paquet que manifest.json
+	for _, f := range bundle.files {
+		if f.Name != m.Entry {
+			continue
+		}
+		script, err := readZipFile(f, maxBundleSize)
+		if err != nil {
+			return nil, err
+		}
+		if scriptManifest, err := manifest.Extract(string(script)); err == nil && scriptManifest.PackageID != m.PackageID {
+			return nil, fmt.Errorf("package_id du script (%s) différent de %s (%s)", scriptManifest.PackageID, bundleManifestName, m.PackageID)
+		}
+	}
+
+	filename := m.PackageID + "/" + m.Entry
+	report, err := a.planImport(&m.Manifest, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Le dossier du paquet est remplacé en bloc : seuls les exemplaires
+	// installés ailleurs (fichiers uniques, autres dossiers) sont des doublons
+	inPackageDir := func(name string) bool {
+		return strings.HasPrefix(filepath.ToSlash(name), m.PackageID+"/") && a.bundleDir(name) != ""
+	}
+	if report.Filename != filename {
+		report.Duplicates = append(report.Duplicates, report.Filename)
+		report.Filename = filename
+	}
+	duplicates := report.Duplicates[:0]
+	for _, duplicate := range report.Duplicates {
+		if !inPackageDir(duplicate) {
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	report.Duplicates = duplicates
+
+	if _, err := os.Stat(filepath.Join(a.getGeneratorsDir(), m.PackageID)); err == nil && report.Action == ImportInstall {
+		report.Conflicts = append(report.Conflicts, fmt.Sprintf("le dossier %s existe déjà", m.PackageID))
+	}
+	return report, nil
+}
+
+// ImportGeneratorBundle installe un bundle .vcgen préalablement envoyé dans
+// le dossier temporaire. Les règles d'ImportGenerator s'appliquent : le
+// package_id identifie le générateur et un retour en arrière doit être
+// autorisé explicitement.
+func (a *App) ImportGeneratorBundle(tempName string, allowDowngrade bool) (*ImportReport, error) {
+	bundle, err := openTempBundle(tempName)
+	if err != nil {
+		return nil, err
+	}
+	defer bundle.Close()
+
+	report, err := a.planBundleImport(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	m := bundle.manifest
+	generatorsDir := a.getGeneratorsDir()
+	packageDir := filepath.Join(generatorsDir, m.PackageID)
+
+	if err := checkImport(report, allowDowngrade); err != nil {
+		return report, err
+	}
+	if err := ensureDiskSpace(generatorsDir, bundle.size); err != nil {
+		return report, err
+	}
+
+	// Décompression à côté puis échange, pour ne jamais laisser un bundle à moitié installé
+	stagingDir := filepath.Join(generatorsDir, "."+m.PackageID+".import")
+	os.RemoveAll(stagingDir)
+	if err := bundle.extractTo(stagingDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return report, err
+	}
+	if err := os.RemoveAll(packageDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return report, fmt.Errorf("erreur suppression de l'ancienne version: %w", err)
+	}
+	if err := os.Rename(stagingDir, packageDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return report, fmt.Errorf("erreur installation du bundle: %w", err)
+	}
+	a.invalidateGenerator(m.PackageID + "/" + bundleManifestName)
+
+	if err := a.finishImport(report, m.Config); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// ExportGeneratorBundle crée un bundle .vcgen dans le dossier temporaire et
+// renvoie son nom, à passer ensuite à ExportTo. Un générateur en fichier
+// unique est converti : son manifest est extrait dans manifest.json.
+func (a *App) ExportGeneratorBundle(filename string) (string, error) {
+	tempDir := getTempDir()
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return "", fmt.Errorf("erreur création dossier temporaire: %w", err)
+	}
+
+	var m *bundleManifest
+	var files map[string]string
+	var script string
+
+	if dir := a.bundleDir(filename); dir != "" {
+		var err error
+		if m, err = readBundleManifest(dir); err != nil {
+			return "", err
+		}
+		if files, err = collectBundleFiles(dir); err != nil {
+			return "", err
+		}
+	} else {
+		content, err := a.LoadGenerator(filename)
+		if err != nil {
+			return "", err
+		}
+		extracted, err := manifest.Extract(content)
+		if err != nil {
+			return "", err
+		}
+		m = &bundleManifest{Manifest: *extracted, Entry: path.Base(filepath.ToSlash(filename))}
+		if err := m.validate(); err != nil {
+			return "", err
+		}
+		script = content
+	}
+
+	bundleName := fmt.Sprintf("%d_%s%s", time.Now().UnixMilli(), m.PackageID, bundleExtension)
+	bundlePath := filepath.Join(tempDir, bundleName)
+
+	out, err := os.Create(bundlePath)
+	if err != nil {
+		return "", fmt.Errorf("erreur création du bundle: %w", err)
+	}
+	zw := zip.NewWriter(out)
+
+	err = writeBundle(zw, m, files, script)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(bundlePath)
+		return "", fmt.Errorf("erreur écriture du bundle: %w", err)
+	}
+
+	return bundleName, nil
+}
+
+// collectBundleFiles liste les fichiers à archiver, avec les mêmes filtres
+// que la surveillance des dossiers liés (fichiers cachés, .git, node_modules)
+// et les limites appliquées à l'import.
+func collectBundleFiles(dir string) (map[string]string, error) {
+	files := make(map[string]string) // chemin dans l'archive -> chemin sur disque
+	var size int64
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		if ignoredPath(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		files[filepath.ToSlash(rel)] = p
+
+		if len(files) > maxBundleFiles {
+			return fmt.Errorf("bundle trop volumineux: plus de %d fichiers", maxBundleFiles)
+		}
+		if size > maxBundleSize {
+			return fmt.Errorf("bundle trop volumineux: plus de %s", formatBytes(maxBundleSize))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture du bundle: %w", err)
+	}
+	return files, nil
+}
+
+func writeBundle(zw *zip.Writer, m *bundleManifest, files map[string]string, script string) error {
+	if _, ok := files[bundleManifestName]; !ok {
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		w, err := zw.Create(bundleManifestName)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if script != "" {
+		w, err := zw.Create(m.Entry)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, script); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		diskPath := files[name]
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		in, err := os.Open(diskPath)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, in)
+		in.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
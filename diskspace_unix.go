@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+func freeDiskSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	// Bavail : blocs disponibles pour un utilisateur non privilégié
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const defaultFilenameTemplate = "{generator}_{date}_{resolution}"
+
+// filenameReplacer retire les caractères interdits dans un nom de fichier
+// sous Windows, macOS ou Linux.
+var filenameReplacer = strings.NewReplacer(
+	"/", "-", `\`, "-", ":", "-", "*", "", "?", "", `"`, "", "<", "", ">", "", "|", "",
+)
+
+func sanitizeFilename(name string) string {
+	name = filenameReplacer.Replace(strings.TrimSpace(name))
+	name = strings.Join(strings.Fields(name), "-")
+	return strings.Trim(name, ".-")
+}
+
+func validateFilenameTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	if strings.ContainsAny(template, `/\`) {
+		return fmt.Errorf("le modèle de nom de fichier ne peut pas contenir de dossier: %s", template)
+	}
+	if renderFilenameTemplate(template, "test", "1920x1080", time.Now()) == "" {
+		return fmt.Errorf("le modèle de nom de fichier produit un nom vide: %s", template)
+	}
+	return nil
+}
+
+// renderFilenameTemplate remplace {generator}, {date} et {resolution} dans le
+// modèle, sans extension.
+func renderFilenameTemplate(template, generator, resolution string, date time.Time) string {
+	if template == "" {
+		template = defaultFilenameTemplate
+	}
+	name := strings.NewReplacer(
+		"{generator}", generator,
+		"{date}", date.Format("2006-01-02_15-04-05"),
+		"{resolution}", resolution,
+	).Replace(template)
+	return sanitizeFilename(name)
+}
+
+// BuildExportFilename propose un nom de fichier d'export à partir du modèle
+// configuré dans les réglages.
+func (a *App) BuildExportFilename(generator, resolution, extension string) string {
+	name := renderFilenameTemplate(a.GetSettings().FilenameTemplate, generator, resolution, time.Now())
+	if name == "" {
+		name = "video"
+	}
+	return name + "." + strings.TrimPrefix(extension, ".")
+}
+
+// defaultOutputDir renvoie le dossier d'export configuré, ou à défaut le
+// dossier Vidéos de l'utilisateur s'il existe.
+func (a *App) defaultOutputDir() string {
+	if dir := a.GetSettings().OutputDir; dir != "" {
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+			return dir
+		}
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	for _, name := range []string{"Videos", "Movies"} {
+		dir := filepath.Join(homeDir, name)
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+			return dir
+		}
+	}
+	return homeDir
+}
+
+func (a *App) SelectOutputDirectory() (string, error) {
+	return wailsruntime.OpenDirectoryDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title:            "Choisir le dossier d'export",
+		DefaultDirectory: a.defaultOutputDir(),
+	})
+}
+
+// ExportTo demande où enregistrer un fichier du dossier temporaire puis l'y
+// déplace. Renvoie le chemin choisi, ou une chaîne vide si l'utilisateur a
+// annulé : le fichier temporaire est alors conservé, à l'appelant de le
+// proposer de nouveau ou de le supprimer.
+func (a *App) ExportTo(tempName, suggestedName string) (string, error) {
+	tempPath, err := tempFilePath(tempName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(tempPath); err != nil {
+		return "", fmt.Errorf("fichier à exporter introuvable: %w", err)
+	}
+
+	ext := filepath.Ext(tempName)
+	if suggestedName == "" {
+		suggestedName = "video" + ext
+	}
+
+	options := wailsruntime.SaveDialogOptions{
+		Title:            "Enregistrer la vidéo",
+		DefaultDirectory: a.defaultOutputDir(),
+		DefaultFilename:  suggestedName,
+	}
+	if ext != "" {
+		options.Filters = []wailsruntime.FileFilter{
+			{DisplayName: fmt.Sprintf("%s (*%s)", strings.ToUpper(ext[1:]), ext), Pattern: "*" + ext},
+		}
+	}
+
+	destination, err := wailsruntime.SaveFileDialog(a.ctx, options)
+	if err != nil {
+		return "", fmt.Errorf("erreur boîte de dialogue: %w", err)
+	}
+	if destination == "" {
+		return "", nil
+	}
+	if filepath.Ext(destination) == "" {
+		destination += ext
+	}
+
+	if err := moveFile(tempPath, destination); err != nil {
+		return "", err
+	}
+	return destination, nil
+}
+
+// moveFile déplace un fichier, avec une copie quand la destination est sur un
+// autre volume que le dossier temporaire.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	// Le renommage a échoué (autre disque) : la copie doit tenir à destination
+	if stat, err := os.Stat(src); err == nil {
+		if err := ensureDiskSpace(filepath.Dir(dst), stat.Size()); err != nil {
+			return err
+		}
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("erreur lecture %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("erreur création %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return fmt.Errorf("erreur copie vers %s: %w", dst, err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return fmt.Errorf("erreur écriture %s: %w", dst, err)
+	}
+
+	in.Close()
+	os.Remove(src)
+	return nil
+}
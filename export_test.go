@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderFilenameTemplate(t *testing.T) {
+	date := time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC)
+	tests := []struct {
+		template  string
+		generator string
+		want      string
+	}{
+		{"", "Balle", "Balle_2024-03-09_14-05-07_1920x1080"},
+		{"{generator}-{resolution}", "Balle", "Balle-1920x1080"},
+		{"export", "Balle", "export"},
+		{"{generator}", "Balle rebondissante", "Balle-rebondissante"},
+		{"{generator}", `a:b*c?"d<e>f|g`, "a-bcdefg"},
+		{"{generator}", "../../etc/passwd", "etc-passwd"},
+		{"{generator}", "  . ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := renderFilenameTemplate(tt.template, tt.generator, "1920x1080", date); got != tt.want {
+			t.Errorf("renderFilenameTemplate(%q, %q) = %q ; attendu %q", tt.template, tt.generator, got, tt.want)
+		}
+	}
+}
+
+func TestValidateFilenameTemplate(t *testing.T) {
+	for _, template := range []string{"", defaultFilenameTemplate, "export-{date}"} {
+		if err := validateFilenameTemplate(template); err != nil {
+			t.Errorf("validateFilenameTemplate(%q) : %v", template, err)
+		}
+	}
+	for _, template := range []string{"exports/{generator}", `exports\{generator}`, "...", "  "} {
+		if err := validateFilenameTemplate(template); err == nil {
+			t.Errorf("validateFilenameTemplate(%q) : erreur attendue", template)
+		}
+	}
+}
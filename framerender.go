@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"VibeCraft/pkg/ffmpeg"
+)
+
+const jobFrameRender = "frame-render"
+
+type frameRender struct {
+	mu         sync.Mutex
+	sink       *ffmpeg.FrameSink
+	outputName string
+	done       func()
+}
+
+// BeginFrameRender prépare un rendu image par image : le frontend envoie
+// ensuite chaque frame avec PushFrame puis termine avec EndFrameRender.
+// frames est le nombre total d'images, qui sert à estimer la place requise.
+func (a *App) BeginFrameRender(width, height, fps, frames int, profile string) error {
+	a.render.mu.Lock()
+	defer a.render.mu.Unlock()
+
+	if a.render.sink != nil {
+		return fmt.Errorf("un rendu est déjà en cours")
+	}
+
+	if profile == "" {
+		profile = a.GetSettings().DefaultProfile
+	}
+	opts, err := ffmpeg.GetProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	tempDir := getTempDir()
+	os.MkdirAll(tempDir, 0755)
+	// Les frames sont encodées au fil de l'eau : seule la vidéo finale occupe
+	// le disque
+	var needed int64
+	if fps > 0 {
+		needed = opts.EstimateSize(ffmpeg.MediaInfo{
+			Width:     width,
+			Height:    height,
+			FrameRate: float64(fps),
+			Duration:  float64(frames) / float64(fps),
+		})
+	}
+	if err := ensureDiskSpace(tempDir, needed); err != nil {
+		return err
+	}
+
+	ctx, done, err := a.jobs.start(a.ctx, jobFrameRender)
+	if err != nil {
+		return err
+	}
+
+	outputName := fmt.Sprintf("%d_video.mp4", time.Now().UnixMilli())
+	sink, err := a.ffmpeg.NewFrameSink(ctx, filepath.Join(tempDir, outputName), ffmpeg.FrameSinkOptions{
+		Width:  width,
+		Height: height,
+		FPS:    fps,
+		Encode: opts,
+	})
+	if err != nil {
+		done()
+		return err
+	}
+
+	a.render.sink = sink
+	a.render.outputName = outputName
+	a.render.done = done
+	return nil
+}
+
+// PushFrame reçoit une frame encodée en base64 : soit un PNG (éventuellement
+// sous forme de data URL issue de canvas.toDataURL), soit des pixels RGBA bruts.
+func (a *App) PushFrame(data string) error {
+	a.render.mu.Lock()
+	defer a.render.mu.Unlock()
+
+	if a.render.sink == nil {
+		return fmt.Errorf("aucun rendu en cours")
+	}
+
+	if i := strings.Index(data, ";base64,"); i >= 0 {
+		data = data[i+len(";base64,"):]
+	}
+	frame, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("frame invalide: %w", err)
+	}
+
+	if err := a.render.sink.WriteFrame(frame); err != nil {
+		a.render.sink.Abort()
+		a.finishFrameRender()
+		return err
+	}
+	return nil
+}
+
+// EndFrameRender finalise la vidéo et renvoie son nom dans le dossier temporaire.
+func (a *App) EndFrameRender() (string, error) {
+	a.render.mu.Lock()
+	defer a.render.mu.Unlock()
+
+	if a.render.sink == nil {
+		return "", fmt.Errorf("aucun rendu en cours")
+	}
+
+	sink, outputName := a.render.sink, a.render.outputName
+	defer a.finishFrameRender()
+
+	if err := sink.Close(); err != nil {
+		return "", err
+	}
+	return outputName, nil
+}
+
+// AbortFrameRender interrompt le rendu en cours (annulation ou erreur côté
+// frontend) sans finaliser la vidéo partielle, qui est supprimée.
+func (a *App) AbortFrameRender() error {
+	a.render.mu.Lock()
+	defer a.render.mu.Unlock()
+
+	if a.render.sink == nil {
+		return nil
+	}
+	a.render.sink.Abort()
+	a.finishFrameRender()
+	return nil
+}
+
+func (a *App) finishFrameRender() {
+	a.render.done()
+	a.render.sink = nil
+	a.render.outputName = ""
+	a.render.done = nil
+}
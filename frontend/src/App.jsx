@@ -1,4 +1,4 @@
-import React, { useState, useEffect } from 'react';
+import React, { useState, useEffect, useRef } from 'react';
 import { Video, Sparkles } from 'lucide-react';
 import Sidebar from './components/Sidebar';
 import PreviewPanel from './components/PreviewPanel';
@@ -15,7 +15,8 @@ function App() {
     duration: 5,
     framerate: 30,
     format: 'webm',
-    resolution: '1920x1080'
+    resolution: '1920x1080',
+    renderMode: 'realtime'
   });
   const [selectedGenerator, setSelectedGenerator] = useState(null);
   const [generatorParams, setGeneratorParams] = useState({});
@@ -26,6 +27,38 @@ function App() {
   const [showChangelogDialog, setShowChangelogDialog] = useState(false);
   const [changelogInfo, setChangelogInfo] = useState(null);
   const [isManualChangelog, setIsManualChangelog] = useState(false);
+  const selectedFilenameRef = useRef('default');
+  const isRecordingRef = useRef(false);
+
+  useEffect(() => {
+    isRecordingRef.current = isRecording;
+  }, [isRecording]);
+
+  // Rechargement à chaud : le Go signale les générateurs modifiés sur disque.
+  // Un enregistrement en cours n'est jamais interrompu.
+  useEffect(() => {
+    const handleGeneratorChanged = ({ filename, generator }) => {
+      setAvailableGenerators(prev => prev.some(g => g.filename === filename)
+        ? prev.map(g => g.filename === filename ? generator : g)
+        : [...prev, generator]);
+      if (filename === selectedFilenameRef.current && !isRecordingRef.current) {
+        handleGeneratorSelect(filename);
+      }
+    };
+    const handleGeneratorRemoved = ({ filename }) => {
+      setAvailableGenerators(prev => prev.filter(g => g.filename !== filename));
+      if (filename === selectedFilenameRef.current && !isRecordingRef.current) {
+        handleGeneratorSelect('default');
+      }
+    };
+
+    window.runtime.EventsOn('generator-changed', handleGeneratorChanged);
+    window.runtime.EventsOn('generator-removed', handleGeneratorRemoved);
+    return () => {
+      window.runtime.EventsOff('generator-changed');
+      window.runtime.EventsOff('generator-removed');
+    };
+  }, []);
 
   useEffect(() => {
     const initializeApp = async () => {
@@ -129,19 +162,9 @@ function App() {
 
   const loadAvailableGenerators = async () => {
     try {
+      // Le manifest est lu côté Go : inutile d'exécuter chaque générateur
       const generators = await ListGenerators();
-      const enrichedGenerators = await Promise.all(
-        (generators || []).map(async (g) => {
-          try {
-            const instance = await loadGenerator(g.filename);
-            const manifestName = instance.constructor.manifest?.name || g.name || g.filename;
-            return { ...g, name: manifestName };
-          } catch {
-            return g;
-          }
-        })
-      );
-      setAvailableGenerators(enrichedGenerators);
+      setAvailableGenerators(generators || []);
     } catch (error) {
       console.error('Erreur lors du chargement des générateurs:', error);
     }
@@ -173,8 +196,11 @@ function App() {
 
       setGeneratorParams({ ...defaultParams, ...savedConfig });
       setSelectedGenerator(generator);
+      selectedFilenameRef.current = generatorIdentifier;
     } catch (error) {
       console.error('Erreur lors du chargement du générateur:', error);
+      // Le nom est conservé : le générateur sera rechargé une fois corrigé
+      selectedFilenameRef.current = generatorIdentifier;
       const defaultGenerator = new BouncingBallGenerator();
       setSelectedGenerator(defaultGenerator);
       const defaultParams = {};
@@ -243,6 +269,8 @@ function App() {
         <div className="grid grid-cols-1 lg:grid-cols-3 gap-6">
           <Sidebar
             globalSettings={globalSettings}
+            supportsAlpha={!!selectedGenerator?.constructor.manifest?.transparent_background}
+            soundEvents={selectedGenerator?.constructor.manifest?.sound_events || []}
             setGlobalSettings={setGlobalSettings}
             availableGenerators={availableGenerators}
             onGeneratorSelect={handleGeneratorSelect}
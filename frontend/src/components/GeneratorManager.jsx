@@ -1,6 +1,17 @@
 import React, { useState } from 'react';
-import { Upload, File, Trash2, Plus, Check } from 'lucide-react';
-import { SaveGenerator, DeleteGenerator } from '../../wailsjs/go/main/App';
+import { Upload, File, Trash2, Plus, Check, AlertTriangle, Package, FolderOpen, Link2, Unlink } from 'lucide-react';
+import {
+  PreviewGeneratorImport,
+  ImportGenerator,
+  PreviewGeneratorBundle,
+  ImportGeneratorBundle,
+  ExportGeneratorBundle,
+  ExportTo,
+  DeleteTempFile,
+  DeleteGenerator,
+  LinkGeneratorFolder
+} from '../../wailsjs/go/main/App';
+import { uploadTempFile } from '../utils/tempFiles';
 import ConfirmModal from './ConfirmModal';
 
 const GeneratorManager = ({ availableGenerators, onGeneratorSelect, onGeneratorsChange }) => {
@@ -10,51 +21,149 @@ const GeneratorManager = ({ availableGenerators, onGeneratorSelect, onGenerators
   const [modalOpen, setModalOpen] = useState(false);
   const [generatorToDelete, setGeneratorToDelete] = useState(null);
   const [isDragActive, setIsDragActive] = useState(false);
+  const [pendingImport, setPendingImport] = useState(null);
+
+  const isSupportedFile = (file) => file && (file.name.endsWith('.js') || file.name.endsWith('.vcgen'));
 
   const handleFileSelect = (event) => {
     const file = event.target.files[0];
-    if (file && file.name.endsWith('.js')) {
+    if (isSupportedFile(file)) {
       setSelectedFile(file);
       setUploadStatus('');
     } else {
-      setUploadStatus('Veuillez sélectionner un fichier .js valide');
+      setUploadStatus('Veuillez sélectionner un fichier .js ou .vcgen valide');
       setSelectedFile(null);
     }
   };
 
+  const importMessages = {
+    upgrade: (report) => `Mettre à jour "${report.name}" de la version ${report.installedVersion} vers ${report.version} ?`,
+    downgrade: (report) => `La version ${report.version} de "${report.name}" est plus ancienne que celle installée (${report.installedVersion}). Revenir à cette version ?`,
+    same: (report) => `"${report.name}" ${report.version} est déjà installé. Le remplacer ?`,
+  };
+
+  // source contient soit le code du générateur (content), soit le nom du
+  // bundle envoyé dans le dossier temporaire (bundle)
+  const applyImport = async (source, allowDowngrade) => {
+    const report = source.bundle
+      ? await ImportGeneratorBundle(source.bundle, allowDowngrade)
+      : await ImportGenerator(selectedFile.name, source.content, allowDowngrade);
+    let status = report.action === 'install'
+      ? 'Générateur importé avec succès !'
+      : `Générateur mis à jour avec succès (${report.installedVersion} → ${report.version}) !`;
+    if (report.droppedParams?.length) {
+      status += ` Réglages supprimés: ${report.droppedParams.join(', ')}`;
+    }
+    setUploadStatus(status);
+    setSelectedFile(null);
+    onGeneratorsChange();
+
+    const fileInput = document.getElementById('generator-file');
+    if (fileInput) fileInput.value = '';
+  };
+
   const handleImport = async () => {
     if (!selectedFile) return;
 
     setIsUploading(true);
     setUploadStatus('');
 
+    let source = null;
     try {
-      const content = await selectedFile.text();
-      await SaveGenerator(selectedFile.name, content);
+      let preview;
+      if (selectedFile.name.endsWith('.vcgen')) {
+        source = { bundle: `${Date.now()}_${selectedFile.name}` };
+        await uploadTempFile(source.bundle, selectedFile);
+        preview = await PreviewGeneratorBundle(source.bundle);
+      } else {
+        source = { content: await selectedFile.text() };
+        preview = await PreviewGeneratorImport(selectedFile.name, source.content);
+      }
+      if (preview.conflicts?.length) {
+        setUploadStatus('Import impossible: ' + preview.conflicts.join('; '));
+        return;
+      }
+      if (preview.action !== 'install') {
+        setPendingImport({ source, report: preview });
+        source = null;
+        return;
+      }
+      await applyImport(source, false);
+    } catch (error) {
+      console.error('Erreur lors de l\'importation:', error);
+      setUploadStatus('Erreur lors de l\'importation: ' + (error.message || error));
+    } finally {
+      discardSource(source);
+      setIsUploading(false);
+    }
+  };
 
-      setUploadStatus('Générateur importé avec succès !');
-      setSelectedFile(null);
-      onGeneratorsChange();
+  const discardSource = (source) => {
+    if (source?.bundle) {
+      DeleteTempFile(source.bundle).catch(() => {});
+    }
+  };
 
-      const fileInput = document.getElementById('generator-file');
-      if (fileInput) fileInput.value = '';
+  const handleConfirmImport = async () => {
+    const { source, report } = pendingImport;
+    setPendingImport(null);
+    setIsUploading(true);
+    try {
+      await applyImport(source, report.action === 'downgrade');
     } catch (error) {
-      console.error('Erreur lors de l\'importation:', error);
-      setUploadStatus('Erreur lors de l\'importation: ' + error.message);
+      setUploadStatus('Erreur lors de l\'importation: ' + (error.message || error));
     } finally {
+      discardSource(source);
       setIsUploading(false);
     }
   };
 
-  const handleDelete = (generatorFilename) => {
-    setGeneratorToDelete(generatorFilename);
+  const handleCancelImport = () => {
+    discardSource(pendingImport?.source);
+    setPendingImport(null);
+  };
+
+  const handleExport = async (generator) => {
+    let tempName = null;
+    try {
+      tempName = await ExportGeneratorBundle(generator.filename);
+      const suggestedName = `${generator.packageId || generator.name}-${generator.version || '1.0.0'}.vcgen`;
+      const savedPath = await ExportTo(tempName, suggestedName);
+      if (savedPath) {
+        setUploadStatus(`Bundle exporté avec succès: ${savedPath}`);
+      } else {
+        // Annulé : le bundle, rapide à reconstruire, n'est pas conservé
+        DeleteTempFile(tempName).catch(() => {});
+      }
+    } catch (error) {
+      console.error('Erreur lors de l\'export:', error);
+      setUploadStatus('Erreur lors de l\'export: ' + (error.message || error));
+      if (tempName) DeleteTempFile(tempName).catch(() => {});
+    }
+  };
+
+  // Un dossier lié est relu à chaque modification, sans réimport
+  const handleLinkFolder = async () => {
+    try {
+      const generator = await LinkGeneratorFolder();
+      if (generator) {
+        setUploadStatus(`Dossier de "${generator.name}" lié avec succès !`);
+        onGeneratorsChange();
+      }
+    } catch (error) {
+      setUploadStatus('Erreur lors de la liaison: ' + (error.message || error));
+    }
+  };
+
+  const handleDelete = (generatorFilename, linked) => {
+    setGeneratorToDelete({ filename: generatorFilename, linked });
     setModalOpen(true);
   };
 
   const handleConfirmDelete = async () => {
     if (!generatorToDelete) return;
     try {
-      await DeleteGenerator(generatorToDelete);
+      await DeleteGenerator(generatorToDelete.filename);
       onGeneratorsChange();
     } catch (error) {
       console.error('Erreur lors de la suppression:', error);
@@ -89,11 +198,11 @@ const GeneratorManager = ({ availableGenerators, onGeneratorSelect, onGenerators
     e.stopPropagation();
     setIsDragActive(false);
     const file = e.dataTransfer.files[0];
-    if (file && file.name.endsWith('.js')) {
+    if (isSupportedFile(file)) {
       setSelectedFile(file);
       setUploadStatus('');
     } else {
-      setUploadStatus('Veuillez sélectionner un fichier .js valide');
+      setUploadStatus('Veuillez sélectionner un fichier .js ou .vcgen valide');
       setSelectedFile(null);
     }
   };
@@ -111,12 +220,12 @@ const GeneratorManager = ({ availableGenerators, onGeneratorSelect, onGenerators
         <div className="text-center">
           <Upload className="w-6 h-6 text-gray-400 mx-auto mb-2" />
           <div className="text-xs text-gray-600 mb-2">
-            Glisser un fichier .js ou cliquer pour importer
+            Glisser un fichier .js ou .vcgen ou cliquer pour importer
           </div>
           <input
             id="generator-file"
             type="file"
-            accept=".js"
+            accept=".js,.vcgen"
             onChange={handleFileSelect}
             className="hidden"
           />
@@ -127,6 +236,14 @@ const GeneratorManager = ({ availableGenerators, onGeneratorSelect, onGenerators
             <Plus className="w-3 h-3" />
             <span>Choisir un fichier</span>
           </label>
+          <button
+            onClick={handleLinkFolder}
+            title="Lier un dossier de développement, rechargé à chaque modification"
+            className="ml-1 inline-flex items-center space-x-1 text-xs bg-gray-100 hover:bg-gray-200 px-2 py-1 rounded-md transition-colors"
+          >
+            <FolderOpen className="w-3 h-3" />
+            <span>Lier un dossier</span>
+          </button>
         </div>
 
         {selectedFile && (
@@ -176,25 +293,46 @@ const GeneratorManager = ({ availableGenerators, onGeneratorSelect, onGenerators
           </button>
 
           {availableGenerators && availableGenerators.map((generator, index) => {
-            let generatorName = typeof generator === 'string' ? generator : generator.name;
+            const generatorName = typeof generator === 'string' ? generator : generator.name;
             const generatorFilename = typeof generator === 'string' ? generator : generator.filename;
-            if (generator.manifest && generator.manifest.name) {
-              generatorName = generator.manifest.name;
-            }
+            const details = [
+              generator.version && `v${generator.version}`,
+              generator.author
+            ].filter(Boolean).join(' • ');
             return (
               <div key={index} className="flex items-center space-x-1">
                 <button
                   onClick={() => handleGeneratorClick(generatorFilename)}
-                  className="flex-1 flex items-center space-x-2 p-2 bg-gray-50 hover:bg-gray-100 rounded-md transition-colors"
+                  title={generator.parseError || generator.description || generatorFilename}
+                  className="flex-1 min-w-0 flex items-center space-x-2 p-2 bg-gray-50 hover:bg-gray-100 rounded-md transition-colors"
                 >
-                  <div className="w-2 h-2 bg-purple-500 rounded-full"></div>
+                  {generator.parseError ? (
+                    <AlertTriangle className="w-3 h-3 text-amber-500 flex-shrink-0" />
+                  ) : (
+                    <div className="w-2 h-2 bg-purple-500 rounded-full flex-shrink-0"></div>
+                  )}
                   <span className="text-xs text-gray-700 truncate">{generatorName}</span>
+                  {generator.linked && (
+                    <Link2 className="w-3 h-3 text-blue-500 flex-shrink-0" />
+                  )}
+                  {details && (
+                    <span className="text-xs text-gray-400 truncate">{details}</span>
+                  )}
+                </button>
+                <button
+                  onClick={() => handleExport(generator)}
+                  disabled={!generator.packageId}
+                  title="Exporter en bundle .vcgen"
+                  className="p-1 text-gray-500 hover:bg-gray-100 rounded disabled:opacity-30"
+                >
+                  <Package className="w-3 h-3" />
                 </button>
                 <button
-                  onClick={() => handleDelete(generatorFilename)}
+                  onClick={() => handleDelete(generatorFilename, generator.linked)}
+                  title={generator.linked ? 'Délier le dossier' : 'Supprimer'}
                   className="p-1 text-red-500 hover:bg-red-50 rounded"
                 >
-                  <Trash2 className="w-3 h-3" />
+                  {generator.linked ? <Unlink className="w-3 h-3" /> : <Trash2 className="w-3 h-3" />}
                 </button>
               </div>
             );
@@ -209,16 +347,26 @@ const GeneratorManager = ({ availableGenerators, onGeneratorSelect, onGenerators
       </div>
 
       <div className="text-xs text-gray-500">
-        Les fichiers .js contiennent une classe VideoGenerator
+        Les fichiers .js contiennent une classe VideoGenerator, les bundles .vcgen y ajoutent leurs ressources
       </div>
 
       <ConfirmModal
         open={modalOpen}
-        title="Supprimer le générateur"
-        message={`Voulez-vous vraiment supprimer le générateur "${generatorToDelete}" ?`}
+        title={generatorToDelete?.linked ? 'Délier le dossier' : 'Supprimer le générateur'}
+        message={generatorToDelete?.linked
+          ? `Délier "${generatorToDelete.filename}" ? Le dossier de développement n'est pas modifié.`
+          : `Voulez-vous vraiment supprimer le générateur "${generatorToDelete?.filename}" ?`}
         onConfirm={handleConfirmDelete}
         onCancel={handleCancelDelete}
       />
+
+      <ConfirmModal
+        open={!!pendingImport}
+        title="Générateur déjà installé"
+        message={pendingImport ? importMessages[pendingImport.report.action](pendingImport.report) : ''}
+        onConfirm={handleConfirmImport}
+        onCancel={handleCancelImport}
+      />
     </div>
   );
 };
@@ -1,14 +1,38 @@
 import React, { useState, useEffect } from 'react';
-import { Clock, Monitor, FileVideo, Maximize2, Download, CheckCircle } from 'lucide-react';
-import { IsFFmpegInstalled, DownloadFFmpeg } from '../../wailsjs/go/main/App';
+import { Clock, Monitor, FileVideo, Maximize2, Download, CheckCircle, Film, Music, Volume2, Gauge, FolderOpen, X } from 'lucide-react';
+import { IsFFmpegInstalled, DownloadFFmpeg, GetEncodeProfiles, GetSettings, SaveSettings, SetDefaultEncodeProfile, CancelJob, GetFFmpegInfo, SelectAudioFile, GetDefaultLoudnessOptions, SelectOutputDirectory } from '../../wailsjs/go/main/App';
 
-const GlobalSettings = ({ settings, onChange }) => {
+const FFMPEG_FORMATS = [
+  { value: 'mp4', label: 'MP4' },
+  { value: 'gif', label: 'GIF' },
+  { value: 'webp', label: 'WebP' },
+  { value: 'apng', label: 'APNG' },
+];
+
+// Formats d'animation sans piste audio
+const SILENT_FORMATS = ['gif', 'webp', 'apng'];
+
+const ALPHA_FORMATS = [
+  { value: 'webm-alpha', label: 'WebM α' },
+  { value: 'mov', label: 'ProRes 4444 α' },
+];
+
+const GlobalSettings = ({ settings, onChange, supportsAlpha, soundEvents = [] }) => {
   const [ffmpegInstalled, setFFmpegInstalled] = useState(false);
   const [isDownloading, setIsDownloading] = useState(false);
   const [downloadProgress, setDownloadProgress] = useState(0);
+  const [encodeProfiles, setEncodeProfiles] = useState([]);
+  const [ffmpegInfo, setFFmpegInfo] = useState(null);
+  const [ffmpegPath, setFFmpegPath] = useState('');
+  const [outputDir, setOutputDir] = useState('');
+  const [filenameTemplate, setFilenameTemplate] = useState('');
+  const [tempLimits, setTempLimits] = useState({ tempMaxAgeHours: 0, tempMaxSizeMB: 0 });
+  // Le rendu image par image produit toujours un MP4
+  const supportsAudio = settings.renderMode === 'frames' || !SILENT_FORMATS.includes(settings.format);
 
   useEffect(() => {
     checkFFmpegInstallation();
+    loadEncodeProfiles();
     
     const handleFFmpegProgress = (data) => {
       setDownloadProgress(data.progress * 100);
@@ -25,6 +49,21 @@ const GlobalSettings = ({ settings, onChange }) => {
     try {
       const installed = await IsFFmpegInstalled();
       setFFmpegInstalled(installed);
+      setFFmpegInfo(installed ? await GetFFmpegInfo() : null);
+    } catch (error) {
+      // Erreur silencieuse
+    }
+  };
+
+  const loadEncodeProfiles = async () => {
+    try {
+      const [profiles, appSettings] = await Promise.all([GetEncodeProfiles(), GetSettings()]);
+      setEncodeProfiles(profiles || []);
+      setFFmpegPath(appSettings.ffmpegPath || '');
+      setOutputDir(appSettings.outputDir || '');
+      setFilenameTemplate(appSettings.filenameTemplate || '');
+      setTempLimits({ tempMaxAgeHours: appSettings.tempMaxAgeHours, tempMaxSizeMB: appSettings.tempMaxSizeMB });
+      onChange((prev) => ({ ...prev, profile: appSettings.defaultProfile }));
     } catch (error) {
       // Erreur silencieuse
     }
@@ -35,14 +74,64 @@ const GlobalSettings = ({ settings, onChange }) => {
     setDownloadProgress(0);
     try {
       await DownloadFFmpeg();
-      setFFmpegInstalled(true);
+      await checkFFmpegInstallation();
       setIsDownloading(false);
     } catch (error) {
-      alert('Erreur lors du téléchargement de FFmpeg: ' + error.message);
+      if (!String(error).includes('annulé')) {
+        alert('Erreur lors du téléchargement de FFmpeg: ' + (error.message || error));
+      }
       setIsDownloading(false);
     }
   };
 
+  const saveFFmpegPath = async () => {
+    try {
+      const appSettings = await GetSettings();
+      await SaveSettings({ ...appSettings, ffmpegPath: ffmpegPath.trim() });
+      await checkFFmpegInstallation();
+    } catch (error) {
+      alert('Chemin FFmpeg invalide: ' + (error.message || error));
+    }
+  };
+
+  const selectOutputDir = async () => {
+    try {
+      const dir = await SelectOutputDirectory();
+      if (!dir) return;
+      const appSettings = await GetSettings();
+      await SaveSettings({ ...appSettings, outputDir: dir });
+      setOutputDir(dir);
+    } catch (error) {
+      alert('Dossier d\'export invalide: ' + (error.message || error));
+    }
+  };
+
+  const saveFilenameTemplate = async () => {
+    try {
+      const appSettings = await GetSettings();
+      await SaveSettings({ ...appSettings, filenameTemplate: filenameTemplate.trim() });
+    } catch (error) {
+      alert('Modèle de nom invalide: ' + (error.message || error));
+    }
+  };
+
+  const saveTempLimits = async () => {
+    try {
+      const appSettings = await GetSettings();
+      await SaveSettings({ ...appSettings, ...tempLimits });
+    } catch (error) {
+      alert('Limites du dossier temporaire invalides: ' + (error.message || error));
+    }
+  };
+
+  const cancelFFmpegDownload = async () => {
+    try {
+      await CancelJob('ffmpeg-download');
+    } catch (error) {
+      // Erreur silencieuse
+    }
+  };
+
   const handleDurationChange = (e) => {
     onChange({
       ...settings,
@@ -64,6 +153,102 @@ const GlobalSettings = ({ settings, onChange }) => {
     });
   };
 
+  const handleProfileChange = async (profile) => {
+    onChange({
+      ...settings,
+      profile: profile
+    });
+    try {
+      await SetDefaultEncodeProfile(profile);
+    } catch (error) {
+      // Erreur silencieuse
+    }
+  };
+
+  const selectMusic = async () => {
+    try {
+      const path = await SelectAudioFile();
+      if (!path) return;
+      onChange({
+        ...settings,
+        music: {
+          path: path,
+          startOffset: 0,
+          trimStart: 0,
+          trimEnd: 0,
+          fadeIn: 0,
+          fadeOut: 1,
+          volume: 1,
+          loop: true,
+          ...(settings.music ? { ...settings.music, path: path } : {})
+        }
+      });
+    } catch (error) {
+      alert('Erreur lors de la sélection de la musique: ' + (error.message || error));
+    }
+  };
+
+  const handleMusicChange = (field, value) => {
+    onChange({
+      ...settings,
+      music: {
+        ...settings.music,
+        [field]: value
+      }
+    });
+  };
+
+  const selectSoundEffect = async (eventName) => {
+    try {
+      const path = await SelectAudioFile();
+      if (!path) return;
+      onChange({
+        ...settings,
+        sfx: {
+          ...settings.sfx,
+          [eventName]: { path: path, volume: settings.sfx?.[eventName]?.volume ?? 1 }
+        }
+      });
+    } catch (error) {
+      alert('Erreur lors de la sélection de l\'effet sonore: ' + (error.message || error));
+    }
+  };
+
+  const removeSoundEffect = (eventName) => {
+    const { [eventName]: removed, ...sfx } = settings.sfx || {};
+    onChange({ ...settings, sfx: sfx });
+  };
+
+  const toggleLoudness = async (enabled) => {
+    if (!enabled) {
+      onChange({ ...settings, loudness: null });
+      return;
+    }
+    try {
+      const defaults = await GetDefaultLoudnessOptions();
+      onChange({ ...settings, loudness: defaults });
+    } catch (error) {
+      alert('Erreur lors de l\'activation de la normalisation: ' + (error.message || error));
+    }
+  };
+
+  const handleLoudnessChange = (field, value) => {
+    onChange({
+      ...settings,
+      loudness: {
+        ...settings.loudness,
+        [field]: value
+      }
+    });
+  };
+
+  const handleRenderModeChange = (renderMode) => {
+    onChange({
+      ...settings,
+      renderMode: renderMode
+    });
+  };
+
   const handleResolutionChange = (resolution) => {
     onChange({
       ...settings,
@@ -159,21 +344,67 @@ const GlobalSettings = ({ settings, onChange }) => {
           >
             WebM
           </button>
+          {[...FFMPEG_FORMATS, ...(supportsAlpha ? ALPHA_FORMATS : [])].map((format) => (
+            <button
+              key={format.value}
+              onClick={() => handleFormatChange(format.value)}
+              disabled={!ffmpegInstalled}
+              className={`px-2 py-1 text-xs rounded-md transition-all ${
+                !ffmpegInstalled
+                  ? 'bg-gray-100 text-gray-400 cursor-not-allowed'
+                  : settings.format === format.value
+                  ? 'bg-green-500 text-white shadow-sm'
+                  : 'bg-gray-100 text-gray-700 hover:bg-gray-200'
+              }`}
+            >
+              {format.label}
+            </button>
+          ))}
+        </div>
+        
+        {ffmpegInstalled && settings.format === 'mp4' && encodeProfiles.length > 0 && (
+          <div className="grid grid-cols-2 gap-1 mt-2">
+            {encodeProfiles.map((profile) => (
+              <button
+                key={profile.name}
+                onClick={() => handleProfileChange(profile.name)}
+                className={`px-2 py-1 text-xs rounded-md transition-all ${settings.profile === profile.name
+                    ? 'bg-blue-500 text-white shadow-sm'
+                    : 'bg-gray-100 text-gray-700 hover:bg-gray-200'
+                  }`}
+              >
+                {profile.label}
+              </button>
+            ))}
+          </div>
+        )}
+
+        {ffmpegInfo && (
+          <p className="text-xs text-gray-500 mt-2 truncate" title={ffmpegInfo.path}>
+            FFmpeg {ffmpegInfo.version} ({ffmpegInfo.source === 'system' ? 'système' : ffmpegInfo.source === 'custom' ? 'personnalisé' : 'VibeCraft'})
+          </p>
+        )}
+
+        {ffmpegInfo?.warning && (
+          <p className="text-xs text-amber-700 mt-1">{ffmpegInfo.warning}</p>
+        )}
+
+        <div className="flex items-center space-x-1 mt-2">
+          <input
+            type="text"
+            value={ffmpegPath}
+            onChange={(e) => setFFmpegPath(e.target.value)}
+            placeholder="Chemin FFmpeg (auto)"
+            className="flex-1 min-w-0 px-2 py-1 text-xs border border-gray-200 rounded-md"
+          />
           <button
-            onClick={() => handleFormatChange('mp4')}
-            disabled={!ffmpegInstalled}
-            className={`px-2 py-1 text-xs rounded-md transition-all ${
-              !ffmpegInstalled
-                ? 'bg-gray-100 text-gray-400 cursor-not-allowed'
-                : settings.format === 'mp4'
-                ? 'bg-green-500 text-white shadow-sm'
-                : 'bg-gray-100 text-gray-700 hover:bg-gray-200'
-            }`}
+            onClick={saveFFmpegPath}
+            className="px-2 py-1 text-xs rounded-md bg-gray-100 text-gray-700 hover:bg-gray-200 transition-all"
           >
-            MP4
+            OK
           </button>
         </div>
-        
+
         {!ffmpegInstalled && !isDownloading && (
           <div className="mt-2 p-2 bg-amber-50 rounded-lg border border-amber-200">
             <p className="text-xs text-amber-800 mb-2">
@@ -193,9 +424,17 @@ const GlobalSettings = ({ settings, onChange }) => {
         
         {isDownloading && (
           <div className="mt-2 p-2 bg-blue-50 rounded-lg border border-blue-200">
-            <p className="text-xs text-blue-800 mb-2">
-              Téléchargement FFmpeg... {downloadProgress.toFixed(1)}%
-            </p>
+            <div className="flex items-center justify-between mb-2">
+              <p className="text-xs text-blue-800">
+                Téléchargement FFmpeg... {downloadProgress.toFixed(1)}%
+              </p>
+              <button
+                onClick={cancelFFmpegDownload}
+                className="text-xs text-blue-700 hover:text-blue-900 underline"
+              >
+                Annuler
+              </button>
+            </div>
             <div className="w-full bg-blue-200 rounded-full h-2">
               <div 
                 className="bg-blue-500 h-2 rounded-full transition-all duration-300"
@@ -206,6 +445,253 @@ const GlobalSettings = ({ settings, onChange }) => {
         )}
       </div>
 
+      <div>
+        <div className="flex items-center space-x-2 mb-2">
+          <Film className="w-3 h-3 text-gray-600" />
+          <label className="text-xs font-medium text-gray-700">Rendu</label>
+        </div>
+        <div className="grid grid-cols-2 gap-1">
+          {[
+            { label: 'Temps réel', value: 'realtime' },
+            { label: 'Image par image', value: 'frames' }
+          ].map((mode) => (
+            <button
+              key={mode.value}
+              onClick={() => handleRenderModeChange(mode.value)}
+              disabled={mode.value === 'frames' && !ffmpegInstalled}
+              className={`px-2 py-1 text-xs rounded-md transition-all ${
+                mode.value === 'frames' && !ffmpegInstalled
+                  ? 'bg-gray-100 text-gray-400 cursor-not-allowed'
+                  : (settings.renderMode || 'realtime') === mode.value
+                  ? 'bg-blue-500 text-white shadow-sm'
+                  : 'bg-gray-100 text-gray-700 hover:bg-gray-200'
+              }`}
+            >
+              {mode.label}
+            </button>
+          ))}
+        </div>
+        {settings.renderMode === 'frames' && (
+          <p className="text-xs text-gray-500 mt-1">
+            Export MP4 avec exactement {settings.duration * settings.framerate} images
+          </p>
+        )}
+      </div>
+
+      <div>
+        <div className="flex items-center space-x-2 mb-2">
+          <FolderOpen className="w-3 h-3 text-gray-600" />
+          <label className="text-xs font-medium text-gray-700">Export</label>
+        </div>
+        <button
+          onClick={selectOutputDir}
+          className="w-full px-2 py-1 text-xs rounded-md bg-gray-100 text-gray-700 hover:bg-gray-200 transition-all truncate text-left"
+          title={outputDir}
+        >
+          {outputDir || 'Dossier par défaut: Vidéos'}
+        </button>
+        <div className="flex items-center space-x-1 mt-1">
+          <input
+            type="text"
+            value={filenameTemplate}
+            onChange={(e) => setFilenameTemplate(e.target.value)}
+            placeholder="{generator}_{date}_{resolution}"
+            className="flex-1 min-w-0 px-2 py-1 text-xs border border-gray-200 rounded-md"
+          />
+          <button
+            onClick={saveFilenameTemplate}
+            className="px-2 py-1 text-xs rounded-md bg-gray-100 text-gray-700 hover:bg-gray-200 transition-all"
+          >
+            OK
+          </button>
+        </div>
+        <div className="grid grid-cols-2 gap-1 mt-1">
+          {[
+            { field: 'tempMaxAgeHours', label: 'Temp. âge max (h)' },
+            { field: 'tempMaxSizeMB', label: 'Temp. taille max (Mo)' }
+          ].map(({ field, label }) => (
+            <label key={field} className="text-xs text-gray-600" title="Nettoyage au démarrage, 0 pour désactiver">
+              {label}
+              <input
+                type="number"
+                min="0"
+                value={tempLimits[field]}
+                onChange={(e) => setTempLimits({ ...tempLimits, [field]: parseInt(e.target.value) || 0 })}
+                onBlur={saveTempLimits}
+                className="w-full px-2 py-1 text-xs border border-gray-200 rounded-md"
+              />
+            </label>
+          ))}
+        </div>
+      </div>
+
+      {ffmpegInstalled && !supportsAudio && (
+        <div className="text-xs text-gray-500 italic">
+          Le format {settings.format.toUpperCase()} n'a pas de piste audio : musique et effets sonores ne sont pas disponibles.
+        </div>
+      )}
+
+      {ffmpegInstalled && supportsAudio && (
+        <div>
+          <div className="flex items-center space-x-2 mb-2">
+            <Music className="w-3 h-3 text-gray-600" />
+            <label className="text-xs font-medium text-gray-700">Musique de fond</label>
+          </div>
+          {settings.music?.path ? (
+            <div className="space-y-2">
+              <div className="flex items-center justify-between bg-gray-50 rounded-md px-2 py-1">
+                <span className="text-xs text-gray-700 truncate" title={settings.music.path}>
+                  {settings.music.path.split(/[\\/]/).pop()}
+                </span>
+                <button
+                  onClick={() => onChange({ ...settings, music: null })}
+                  className="text-gray-400 hover:text-gray-600"
+                >
+                  <X className="w-3 h-3" />
+                </button>
+              </div>
+              <div>
+                <label className="text-xs text-gray-600">Volume: {Math.round(settings.music.volume * 100)}%</label>
+                <input
+                  type="range"
+                  min="0"
+                  max="2"
+                  step="0.05"
+                  value={settings.music.volume}
+                  onChange={(e) => handleMusicChange('volume', parseFloat(e.target.value))}
+                  className="w-full h-2 bg-gray-200 rounded-lg appearance-none cursor-pointer slider"
+                />
+              </div>
+              <div className="grid grid-cols-2 gap-1">
+                {[
+                  { field: 'startOffset', label: 'Décalage (s)' },
+                  { field: 'trimStart', label: 'Début extrait (s)' },
+                  { field: 'fadeIn', label: 'Fondu entrée (s)' },
+                  { field: 'fadeOut', label: 'Fondu sortie (s)' }
+                ].map(({ field, label }) => (
+                  <label key={field} className="text-xs text-gray-600">
+                    {label}
+                    <input
+                      type="number"
+                      min="0"
+                      step="0.1"
+                      value={settings.music[field]}
+                      onChange={(e) => handleMusicChange(field, parseFloat(e.target.value) || 0)}
+                      className="w-full px-2 py-1 text-xs border border-gray-200 rounded-md"
+                    />
+                  </label>
+                ))}
+              </div>
+              <label className="flex items-center space-x-2 text-xs text-gray-600">
+                <input
+                  type="checkbox"
+                  checked={settings.music.loop}
+                  onChange={(e) => handleMusicChange('loop', e.target.checked)}
+                />
+                <span>Boucler jusqu'à la fin de la vidéo</span>
+              </label>
+            </div>
+          ) : (
+            <button
+              onClick={selectMusic}
+              className="w-full px-2 py-1 text-xs rounded-md bg-gray-100 text-gray-700 hover:bg-gray-200 transition-all"
+            >
+              Choisir un fichier audio
+            </button>
+          )}
+        </div>
+      )}
+
+      {ffmpegInstalled && supportsAudio && soundEvents.length > 0 && (
+        <div>
+          <div className="flex items-center space-x-2 mb-2">
+            <Volume2 className="w-3 h-3 text-gray-600" />
+            <label className="text-xs font-medium text-gray-700">Effets sonores</label>
+          </div>
+          <div className="space-y-1">
+            {soundEvents.map((eventName) => {
+              const effect = settings.sfx?.[eventName];
+              return (
+                <div key={eventName} className="flex items-center space-x-2">
+                  <span className="text-xs text-gray-600 w-16 truncate" title={eventName}>{eventName}</span>
+                  {effect ? (
+                    <>
+                      <span className="flex-1 text-xs text-gray-700 truncate bg-gray-50 rounded-md px-2 py-1" title={effect.path}>
+                        {effect.path.split(/[\\/]/).pop()}
+                      </span>
+                      <input
+                        type="range"
+                        min="0"
+                        max="2"
+                        step="0.05"
+                        value={effect.volume}
+                        onChange={(e) => onChange({
+                          ...settings,
+                          sfx: { ...settings.sfx, [eventName]: { ...effect, volume: parseFloat(e.target.value) } }
+                        })}
+                        className="w-16 h-2 bg-gray-200 rounded-lg appearance-none cursor-pointer slider"
+                        title={`Volume: ${Math.round(effect.volume * 100)}%`}
+                      />
+                      <button
+                        onClick={() => removeSoundEffect(eventName)}
+                        className="text-gray-400 hover:text-gray-600"
+                      >
+                        <X className="w-3 h-3" />
+                      </button>
+                    </>
+                  ) : (
+                    <button
+                      onClick={() => selectSoundEffect(eventName)}
+                      className="flex-1 px-2 py-1 text-xs rounded-md bg-gray-100 text-gray-700 hover:bg-gray-200 transition-all"
+                    >
+                      Choisir un son
+                    </button>
+                  )}
+                </div>
+              );
+            })}
+          </div>
+        </div>
+      )}
+
+      {ffmpegInstalled && supportsAudio && (settings.music?.path || Object.keys(settings.sfx || {}).length > 0) && (
+        <div>
+          <div className="flex items-center space-x-2 mb-2">
+            <Gauge className="w-3 h-3 text-gray-600" />
+            <label className="text-xs font-medium text-gray-700">Normalisation du volume</label>
+          </div>
+          <label className="flex items-center space-x-2 text-xs text-gray-600">
+            <input
+              type="checkbox"
+              checked={!!settings.loudness}
+              onChange={(e) => toggleLoudness(e.target.checked)}
+            />
+            <span>Normaliser la sonie (EBU R128)</span>
+          </label>
+          {settings.loudness && (
+            <div className="grid grid-cols-2 gap-1 mt-2">
+              {[
+                { field: 'targetLufs', label: 'Cible (LUFS)', min: -70, max: -5 },
+                { field: 'truePeak', label: 'Crête max (dBTP)', min: -9, max: 0 }
+              ].map(({ field, label, min, max }) => (
+                <label key={field} className="text-xs text-gray-600">
+                  {label}
+                  <input
+                    type="number"
+                    min={min}
+                    max={max}
+                    step="0.5"
+                    value={settings.loudness[field]}
+                    onChange={(e) => handleLoudnessChange(field, parseFloat(e.target.value) || 0)}
+                    className="w-full px-2 py-1 text-xs border border-gray-200 rounded-md"
+                  />
+                </label>
+              ))}
+            </div>
+          )}
+        </div>
+      )}
+
       <div className="bg-blue-50 rounded-lg p-2 mt-3">
         <p className="text-xs text-blue-800">
           Vidéo de <strong>{settings.duration}s</strong> à <strong>{settings.framerate} FPS</strong> en <strong>{settings.resolution}</strong> format <strong>{settings.format.toUpperCase()}</strong>
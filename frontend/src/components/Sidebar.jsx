@@ -3,7 +3,7 @@ import { Settings, Upload } from 'lucide-react';
 import GlobalSettings from './GlobalSettings';
 import GeneratorManager from './GeneratorManager';
 
-const Sidebar = ({ globalSettings, setGlobalSettings, availableGenerators, onGeneratorSelect, onGeneratorsChange }) => {
+const Sidebar = ({ globalSettings, setGlobalSettings, supportsAlpha, soundEvents, availableGenerators, onGeneratorSelect, onGeneratorsChange }) => {
   return (
     <div className="space-y-4">
       <div className="bg-white rounded-xl shadow-sm border border-gray-200 p-4">
@@ -14,6 +14,8 @@ const Sidebar = ({ globalSettings, setGlobalSettings, availableGenerators, onGen
         <GlobalSettings 
           settings={globalSettings} 
           onChange={setGlobalSettings} 
+          supportsAlpha={supportsAlpha}
+          soundEvents={soundEvents}
         />
       </div>
       <div className="bg-white rounded-xl shadow-sm border border-gray-200 p-4">
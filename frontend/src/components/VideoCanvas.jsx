@@ -1,18 +1,118 @@
 import React, { useRef, useEffect, useState } from 'react';
 import { Play, Square, Download, Eye, Loader2 } from 'lucide-react';
-import { ConvertVideoToMP4, SaveTempFile, ReadTempFile, DeleteTempFile } from '../../wailsjs/go/main/App';
+import { ConvertVideoToMP4, ConvertVideoToGIF, GetDefaultGIFOptions, ConvertVideoToWebP, GetDefaultWebPOptions, ConvertVideoToAPNG, GetDefaultAPNGOptions, ConvertVideoWithAlpha, DeleteTempFile, CancelJob, InspectVideo, BeginFrameRender, PushFrame, EndFrameRender, AbortFrameRender, MuxBackgroundMusic, MixSoundEffects, NormalizeLoudness, ExportTo, BuildExportFilename } from '../../wailsjs/go/main/App';
+import { uploadTempFile, fetchTempFile } from '../utils/tempFiles';
+
+const FFMPEG_FORMATS = {
+  mp4: {
+    extension: 'mp4',
+    mimeType: 'video/mp4',
+    audio: true,
+    convert: (input, output, settings) => ConvertVideoToMP4(input, output, settings.profile || '', settings.duration),
+  },
+  gif: {
+    extension: 'gif',
+    mimeType: 'image/gif',
+    convert: async (input, output, settings) => ConvertVideoToGIF(input, output, await GetDefaultGIFOptions(), settings.duration),
+  },
+  webp: {
+    extension: 'webp',
+    mimeType: 'image/webp',
+    convert: async (input, output, settings) => ConvertVideoToWebP(input, output, await GetDefaultWebPOptions(), settings.duration),
+  },
+  apng: {
+    extension: 'png',
+    mimeType: 'image/apng',
+    convert: async (input, output, settings) => ConvertVideoToAPNG(input, output, await GetDefaultAPNGOptions(), settings.duration),
+  },
+  'webm-alpha': {
+    extension: 'webm',
+    mimeType: 'video/webm',
+    audio: true,
+    convert: (input, output, settings) => ConvertVideoWithAlpha(input, output, 'webm', settings.duration),
+  },
+  mov: {
+    extension: 'mov',
+    mimeType: 'video/quicktime',
+    audio: true,
+    convert: (input, output, settings) => ConvertVideoWithAlpha(input, output, 'prores', settings.duration),
+  },
+};
+
+// Chaque étape ffmpeg est un job distinct, nommé d'après son fichier de
+// sortie : onStart le reçoit avant le lancement pour pouvoir l'annuler.
+
+// Ajoute la musique de fond configurée et renvoie le fichier à télécharger.
+// duration est celle de l'enregistrement, que le WebM brut n'annonce pas.
+const applyMusic = async (videoPath, extension, music, duration, onStart) => {
+  if (!music?.path) return videoPath;
+  const musicPath = videoPath.replace(/\.[^.]+$/, `_music.${extension}`);
+  onStart?.(musicPath);
+  await MuxBackgroundMusic(videoPath, musicPath, music, duration);
+  await DeleteTempFile(videoPath);
+  return musicPath;
+};
+
+// Place les effets sonores sur les événements émis par le générateur
+const applySoundEffects = async (videoPath, extension, events, sfx, duration, onStart) => {
+  if (!events.some(event => sfx?.[event.name]?.path)) return videoPath;
+  const sfxPath = videoPath.replace(/\.[^.]+$/, `_sfx.${extension}`);
+  onStart?.(sfxPath);
+  await MixSoundEffects(videoPath, sfxPath, events, sfx, duration);
+  await DeleteTempFile(videoPath);
+  return sfxPath;
+};
+
+// Normalise la sonie de la piste ajoutée et renvoie le fichier avec ses mesures
+const applyLoudness = async (videoPath, extension, loudness, onStart) => {
+  if (!loudness) return { path: videoPath, report: null };
+  const normalizedPath = videoPath.replace(/\.[^.]+$/, `_norm.${extension}`);
+  onStart?.(normalizedPath);
+  const report = await NormalizeLoudness(videoPath, normalizedPath, loudness);
+  await DeleteTempFile(videoPath);
+  return { path: normalizedPath, report: report };
+};
 
 const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, canvasKey }) => {
   const canvasRef = useRef(null);
   const mediaRecorderRef = useRef(null);
   const animationRef = useRef(null);
+  const conversionJobRef = useRef(null);
+  const conversionCancelledRef = useRef(false);
   const [isRecording, setIsRecording] = useState(false);
   const [isPreviewing, setIsPreviewing] = useState(false);
   const [recordedVideo, setRecordedVideo] = useState(null);
   const [isConverting, setIsConverting] = useState(false);
+  const [convertProgress, setConvertProgress] = useState(null);
+  const [renderedFrames, setRenderedFrames] = useState(0);
+  const [loudnessReport, setLoudnessReport] = useState(null);
+  const renderCancelledRef = useRef(false);
+  const soundEventsRef = useRef([]);
+  // Export dont la boîte de dialogue a été annulée : le fichier converti est
+  // conservé pour être proposé de nouveau sans refaire la conversion
+  const pendingExportRef = useRef(null);
 
   const [canvasWidth, canvasHeight] = globalSettings.resolution.split('x').map(Number);
 
+  const discardPendingExport = () => {
+    const pending = pendingExportRef.current;
+    pendingExportRef.current = null;
+    if (pending) {
+      DeleteTempFile(pending.tempName).catch(() => {});
+    }
+  };
+
+  // Un nouvel enregistrement rend l'export en attente obsolète
+  useEffect(() => discardPendingExport, [recordedVideo]);
+
+  useEffect(() => {
+    window.runtime.EventsOn('ffmpeg-convert-progress', setConvertProgress);
+
+    return () => {
+      window.runtime.EventsOff('ffmpeg-convert-progress');
+    };
+  }, []);
+
   useEffect(() => {
     if (generator && params && canvasRef.current) {
       startPreview();
@@ -34,6 +134,7 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
     ctx.clearRect(0, 0, canvas.width, canvas.height);
 
     setIsPreviewing(true);
+    generator.onEvent = null;
     generator.setup(canvas, params);
     
     const animate = () => {
@@ -52,9 +153,76 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
     setIsPreviewing(false);
   };
 
+  const renderFrames = async () => {
+    const canvas = canvasRef.current;
+    const [width, height] = globalSettings.resolution.split('x').map(Number);
+    canvas.width = width;
+    canvas.height = height;
+
+    stopAnimation();
+    setIsRecording(true);
+    onRecordingChange?.(true);
+    setRenderedFrames(0);
+    setLoudnessReport(null);
+    renderCancelledRef.current = false;
+
+    const totalFrames = globalSettings.duration * globalSettings.framerate;
+    let frame = 0;
+    let outputName = null;
+    const events = [];
+    const startJob = (jobId) => {
+      if (renderCancelledRef.current) {
+        throw new Error('Rendu annulé');
+      }
+      conversionJobRef.current = jobId;
+    };
+    try {
+      await BeginFrameRender(width, height, globalSettings.framerate, totalFrames, globalSettings.profile || '');
+      generator.onEvent = (name) => events.push({ name: name, time: frame / globalSettings.framerate });
+      generator.setup(canvas, params);
+
+      for (frame = 0; frame < totalFrames; frame++) {
+        if (renderCancelledRef.current) {
+          throw new Error('Rendu annulé');
+        }
+        generator.draw(canvas, params);
+        await PushFrame(canvas.toDataURL('image/png'));
+        setRenderedFrames(frame + 1);
+      }
+
+      outputName = await EndFrameRender();
+      outputName = await applyAudio(outputName, 'mp4', events, (jobId) => {
+        startJob(jobId);
+        outputName = jobId;
+      });
+      const video = await fetchTempFile(outputName, 'video/mp4');
+      await DeleteTempFile(outputName);
+      setRecordedVideo(video);
+    } catch (error) {
+      // Ne finalise pas la vidéo partielle : elle est supprimée
+      await AbortFrameRender().catch(() => {});
+      if (outputName) {
+        DeleteTempFile(outputName).catch(() => {});
+      }
+      if (!renderCancelledRef.current) {
+        alert(`Erreur lors du rendu image par image: ${error.message || error}`);
+      }
+    } finally {
+      conversionJobRef.current = null;
+      setIsRecording(false);
+      onRecordingChange?.(false);
+      startPreview();
+    }
+  };
+
   const startRecording = async () => {
     if (!generator || !canvasRef.current || isRecording) return;
 
+    if (globalSettings.renderMode === 'frames') {
+      await renderFrames();
+      return;
+    }
+
     const canvas = canvasRef.current;
     const [width, height] = globalSettings.resolution.split('x').map(Number);
     canvas.width = width;
@@ -113,9 +281,14 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
     setIsRecording(true);
     onRecordingChange?.(true);
     
+    const events = [];
+    soundEventsRef.current = events;
+    let recordingStart = performance.now();
+    generator.onEvent = (name) => events.push({ name: name, time: (performance.now() - recordingStart) / 1000 });
     generator.setup(canvas, params);
     
     mediaRecorderRef.current.start(100); 
+    recordingStart = performance.now();
     
     const recordingAnimate = () => {
       generator.draw(canvas, params);
@@ -134,62 +307,156 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
     }, globalSettings.duration * 1000);
   };
 
+  // Réglages dont dépend le fichier produit par downloadVideo
+  const exportKey = () => JSON.stringify([
+    globalSettings.format, globalSettings.profile, globalSettings.music, globalSettings.sfx, globalSettings.loudness,
+  ]);
+
+  // Enregistre un fichier temporaire à l'emplacement choisi par l'utilisateur
+  const saveExport = async (tempName, extension) => {
+    const generatorName = generator?.constructor.manifest?.name || 'video';
+    const suggestedName = await BuildExportFilename(generatorName, globalSettings.resolution, extension);
+    const savedPath = await ExportTo(tempName, suggestedName);
+    if (!savedPath) {
+      pendingExportRef.current = { tempName, extension, key: exportKey() };
+    }
+    return savedPath;
+  };
+
+  const saveBlob = async (blob, extension) => {
+    const tempName = `${Date.now()}_export.${extension}`;
+    await uploadTempFile(tempName, blob);
+    try {
+      return await saveExport(tempName, extension);
+    } catch (error) {
+      DeleteTempFile(tempName).catch(() => {});
+      throw error;
+    }
+  };
+
+  // Musique, effets sonores puis normalisation, selon les réglages
+  const applyAudio = async (videoPath, extension, events, onStart) => {
+    const duration = globalSettings.duration;
+    let outputPath = await applyMusic(videoPath, extension, globalSettings.music, duration, onStart);
+    outputPath = await applySoundEffects(outputPath, extension, events, globalSettings.sfx, duration, onStart);
+    if (outputPath !== videoPath) {
+      const normalized = await applyLoudness(outputPath, extension, globalSettings.loudness, onStart);
+      outputPath = normalized.path;
+      setLoudnessReport(normalized.report);
+    }
+    return outputPath;
+  };
+
   const downloadVideo = async () => {
     if (!recordedVideo) return;
 
-    if (globalSettings.format === 'mp4') {
+    const pending = pendingExportRef.current;
+    if (pending && pending.key === exportKey()) {
+      pendingExportRef.current = null;
+      try {
+        await saveExport(pending.tempName, pending.extension);
+      } catch (error) {
+        DeleteTempFile(pending.tempName).catch(() => {});
+        alert(`Erreur lors de l'export: ${error.message || error}`);
+      }
+      return;
+    }
+    discardPendingExport();
+
+    const ffmpegFormat = FFMPEG_FORMATS[globalSettings.format];
+    // Le WebM enregistré passe par ffmpeg seulement pour y ajouter l'audio
+    const hasAudio = !!globalSettings.music?.path ||
+      soundEventsRef.current.some(event => globalSettings.sfx?.[event.name]?.path);
+    if (recordedVideo.type === 'video/mp4') {
+      try {
+        await saveBlob(recordedVideo, 'mp4');
+      } catch (error) {
+        alert(`Erreur lors de l'export: ${error.message || error}`);
+      }
+    } else if (ffmpegFormat || (globalSettings.format === 'webm' && hasAudio)) {
+      const format = globalSettings.format;
+      const extension = ffmpegFormat?.extension || 'webm';
       setIsConverting(true);
+      setConvertProgress(null);
+      setLoudnessReport(null);
+      conversionCancelledRef.current = false;
+      const timestamp = Date.now();
+      const tempWebmPath = `${timestamp}_temp.webm`;
+      let outputPath = ffmpegFormat ? `${timestamp}_video.${extension}` : tempWebmPath;
+      // Annuler vise l'étape en cours ; entre deux étapes, la suivante n'est pas lancée
+      const startJob = (jobId) => {
+        if (conversionCancelledRef.current) {
+          throw new Error('Conversion annulée');
+        }
+        conversionJobRef.current = jobId;
+      };
       try {
-        const webmBlob = recordedVideo;
-        const webmArrayBuffer = await webmBlob.arrayBuffer();
-        const webmUint8Array = new Uint8Array(webmArrayBuffer);
-        
-        const timestamp = Date.now();
-        const tempWebmPath = `${timestamp}_temp.webm`;
-        const mp4Path = `${timestamp}_video.mp4`;
-        
-        await SaveTempFile(tempWebmPath, Array.from(webmUint8Array));
-        await ConvertVideoToMP4(tempWebmPath, mp4Path);
-        
-        const mp4Data = await ReadTempFile(mp4Path);
-        
-        if (!mp4Data || mp4Data.length === 0) {
-          throw new Error('Fichier MP4 vide reçu du backend');
+        await uploadTempFile(tempWebmPath, recordedVideo);
+        if (ffmpegFormat) {
+          startJob(outputPath);
+          await ffmpegFormat.convert(tempWebmPath, outputPath, globalSettings);
         }
-        
-        const uint8Array = new Uint8Array(mp4Data);
-        const mp4Blob = new Blob([uint8Array], { type: 'video/mp4' });
-        
-        if (mp4Blob.size === 0) {
-          throw new Error('Erreur lors de la création du fichier MP4');
+        if (!ffmpegFormat || ffmpegFormat.audio) {
+          outputPath = await applyAudio(outputPath, extension, soundEventsRef.current, startJob);
         }
         
-        const url = URL.createObjectURL(mp4Blob);
-        const link = document.createElement('a');
-        link.href = url;
-        link.download = `satisfying-video-${timestamp}.mp4`;
-        document.body.appendChild(link);
-        link.click();
-        document.body.removeChild(link);
-        URL.revokeObjectURL(url);
-        
-        await DeleteTempFile(tempWebmPath);
-        await DeleteTempFile(mp4Path);
+        try {
+          const info = await InspectVideo(outputPath);
+          const [expectedWidth, expectedHeight] = globalSettings.resolution.split('x').map(Number);
+          if (format === 'mp4' && (info.width !== expectedWidth || info.height !== expectedHeight) ||
+              Math.abs(info.duration - globalSettings.duration) > 0.5) {
+            console.warn(`Vidéo exportée différente des réglages: ${info.width}x${info.height}, ${info.duration.toFixed(2)}s`);
+          }
+        } catch (error) {
+          // ffprobe indisponible, vérification ignorée
+        }
+
+        if (ffmpegFormat) {
+          await DeleteTempFile(tempWebmPath);
+        }
+        if (conversionCancelledRef.current) {
+          throw new Error('Conversion annulée');
+        }
+        await saveExport(outputPath, extension);
         
         setIsConverting(false);
       } catch (error) {
-        alert(`Erreur lors de la conversion MP4: ${error.message}`);
+        DeleteTempFile(tempWebmPath).catch(() => {});
+        DeleteTempFile(outputPath).catch(() => {});
+        if (!conversionCancelledRef.current) {
+          alert(`Erreur lors de la conversion ${format.toUpperCase()}: ${error.message || error}`);
+        }
         setIsConverting(false);
+      } finally {
+        conversionJobRef.current = null;
       }
     } else {
-      const url = URL.createObjectURL(recordedVideo);
-      const link = document.createElement('a');
-      link.href = url;
-      link.download = `satisfying-video-${Date.now()}.${globalSettings.format}`;
-      document.body.appendChild(link);
-      link.click();
-      document.body.removeChild(link);
-      URL.revokeObjectURL(url);
+      try {
+        await saveBlob(recordedVideo, globalSettings.format);
+      } catch (error) {
+        alert(`Erreur lors de l'export: ${error.message || error}`);
+      }
+    }
+  };
+
+  const cancelConversion = async () => {
+    const jobId = conversionJobRef.current;
+    conversionCancelledRef.current = true;
+    if (!jobId) return;
+    conversionJobRef.current = null;
+    try {
+      await CancelJob(jobId);
+    } catch (error) {
+      // La conversion est peut-être déjà terminée
+    }
+  };
+
+  // Interrompt la boucle de frames, ou l'étape audio qui la suit
+  const cancelRender = () => {
+    renderCancelledRef.current = true;
+    const jobId = conversionJobRef.current;
+    if (jobId) {
+      CancelJob(jobId).catch(() => {});
     }
   };
 
@@ -274,8 +541,18 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
           <div className="flex items-center space-x-2">
             <div className="w-2 h-2 bg-red-500 rounded-full animate-pulse"></div>
             <span className="text-sm text-red-800">
-              Enregistrement en cours... ({globalSettings.duration}s restantes)
+              {globalSettings.renderMode === 'frames'
+                ? `Rendu image par image... (${renderedFrames}/${globalSettings.duration * globalSettings.framerate})`
+                : `Enregistrement en cours... (${globalSettings.duration}s restantes)`}
             </span>
+            {globalSettings.renderMode === 'frames' && (
+              <button
+                onClick={cancelRender}
+                className="ml-auto text-xs text-red-700 hover:text-red-900 underline"
+              >
+                Annuler
+              </button>
+            )}
           </div>
         </div>
       )}
@@ -288,9 +565,9 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
               <div className="text-sm text-green-800">
                 <div>
                   Vidéo générée avec succès ! ({(recordedVideo.size / 1024 / 1024).toFixed(2)} MB)
-                  {globalSettings.format === 'mp4' && (
+                  {FFMPEG_FORMATS[globalSettings.format] && (
                     <span className="ml-2 text-xs bg-green-100 text-green-700 px-2 py-1 rounded">
-                      MP4 avec conversion FFmpeg
+                      {globalSettings.format.toUpperCase()} avec conversion FFmpeg
                     </span>
                   )}
                 </div>
@@ -300,6 +577,12 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
                   Durée: {globalSettings.duration}s • 
                   {globalSettings.framerate} FPS
                 </div>
+                {loudnessReport && (
+                  <div className="text-xs text-green-600 mt-1">
+                    Sonie: {loudnessReport.inputI.toFixed(1)} LUFS → {(loudnessReport.outputI || loudnessReport.target.targetLufs).toFixed(1)} LUFS •
+                    Crête: {loudnessReport.inputTp.toFixed(1)} → {(loudnessReport.outputTp || loudnessReport.target.truePeak).toFixed(1)} dBTP
+                  </div>
+                )}
               </div>
             </div>
           </div>
@@ -311,9 +594,26 @@ const VideoCanvas = ({ generator, params, globalSettings, onRecordingChange, can
           <div className="flex items-center space-x-2">
             <Loader2 className="w-4 h-4 animate-spin text-blue-500" />
             <span className="text-sm text-blue-800">
-              Conversion en MP4 en cours...
+              Conversion en {globalSettings.format.toUpperCase()} en cours...
+              {convertProgress && convertProgress.duration > 0 && (
+                <> {convertProgress.percent.toFixed(0)}% (~{Math.ceil(convertProgress.eta)}s restantes)</>
+              )}
             </span>
+            <button
+              onClick={cancelConversion}
+              className="ml-auto text-xs text-blue-700 hover:text-blue-900 underline"
+            >
+              Annuler
+            </button>
           </div>
+          {convertProgress && convertProgress.duration > 0 && (
+            <div className="w-full bg-blue-200 rounded-full h-2 mt-2">
+              <div
+                className="bg-blue-500 h-2 rounded-full transition-all duration-300"
+                style={{ width: `${convertProgress.percent}%` }}
+              />
+            </div>
+          )}
         </div>
       )}
     </div>
@@ -6,6 +6,7 @@ const MANIFEST = {
   api_version: "1.0",
   description: "Générateur de balle rebondissante avec physique réaliste",
   author: "VibeCraft",
+  sound_events: ['bounce'],
   config: [
     { type: 'number', name: 'speed', default: 3, min: 1, max: 10, label: 'Vitesse initiale de la balle' },
     { type: 'color', name: 'ballColor', default: '#ff0000', label: 'Couleur de la balle' },
@@ -130,6 +131,8 @@ export class BouncingBallGenerator extends VideoGenerator {
       this.vx *= this.friction;
       this.vy *= this.friction;
 
+      this.emit('bounce');
+
       if (params.growthOnBounce > 0) {
         this.currentRadius += params.growthOnBounce * scaleFactor;
         if (this.currentRadius >= this.circleRadius) {
@@ -5,6 +5,10 @@
 export class VideoGenerator {
   /**
    * Retourne le manifest du générateur
+   * Le champ optionnel transparent_background indique que le générateur ne
+   * peint pas de fond, ce qui active les exports avec canal alpha.
+   * Le champ optionnel sound_events liste les événements émis via emit(),
+   * auxquels l'utilisateur peut associer un effet sonore.
    * @returns {Object} Manifest du générateur
    */
   static get manifest() {
@@ -15,6 +19,8 @@ export class VideoGenerator {
       api_version: "1.0",
       description: "Générateur de base",
       author: "VibeCraft",
+      transparent_background: false,
+      sound_events: [],
       config: []
     };
   }
@@ -46,6 +52,25 @@ export class VideoGenerator {
     // À implémenter dans les classes dérivées
   }
 
+  /**
+   * Signale un événement (rebond, explosion...) pendant l'enregistrement
+   * @param {string} name Nom de l'événement, déclaré dans sound_events
+   */
+  emit(name) {
+    this.onEvent?.(name);
+  }
+
+  /**
+   * Retourne l'URL d'une ressource du bundle, utilisable avec new Image() ou FontFace
+   * @param {string} path Chemin dans le bundle, par exemple 'assets/logo.png'
+   * @returns {string} URL servie par l'application
+   */
+  assetUrl(path) {
+    const packageId = encodeURIComponent(this.constructor.manifest.package_id);
+    const encodedPath = path.split('/').map(encodeURIComponent).join('/');
+    return `/gen-assets/${packageId}/${encodedPath}`;
+  }
+
   /**
    * Méthode optionnelle pour nettoyer les ressources
    */
@@ -0,0 +1,44 @@
+import { BeginTempUpload, AppendTempUpload, CommitTempUpload, AbortTempUpload } from '../../wailsjs/go/main/App';
+
+// Taille des morceaux envoyés au backend, avant encodage base64
+const CHUNK_SIZE = 4 * 1024 * 1024;
+
+const blobToBase64 = (blob) => new Promise((resolve, reject) => {
+  const reader = new FileReader();
+  reader.onload = () => resolve(reader.result.slice(reader.result.indexOf(',') + 1));
+  reader.onerror = () => reject(reader.error);
+  reader.readAsDataURL(blob);
+});
+
+/**
+ * Envoie un Blob dans le dossier temporaire par morceaux, sans le convertir
+ * en tableau JSON
+ */
+export async function uploadTempFile(filename, blob) {
+  await BeginTempUpload(filename, blob.size);
+  try {
+    for (let offset = 0; offset < blob.size; offset += CHUNK_SIZE) {
+      await AppendTempUpload(filename, await blobToBase64(blob.slice(offset, offset + CHUNK_SIZE)));
+    }
+    await CommitTempUpload(filename);
+  } catch (error) {
+    try {
+      await AbortTempUpload(filename);
+    } catch (e) {
+      // Envoi déjà interrompu côté backend
+    }
+    throw error;
+  }
+}
+
+/**
+ * Récupère un fichier du dossier temporaire via l'AssetServer
+ */
+export async function fetchTempFile(filename, mimeType) {
+  const response = await fetch(`/temp/${encodeURIComponent(filename)}`);
+  if (!response.ok) {
+    throw new Error(`Lecture de ${filename} impossible (${response.status})`);
+  }
+  const blob = await response.blob();
+  return mimeType ? new Blob([blob], { type: mimeType }) : blob;
+}
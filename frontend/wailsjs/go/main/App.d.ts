@@ -1,11 +1,36 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {autoupdater} from '../models';
+import {ffmpeg} from '../models';
 import {main} from '../models';
 
+export function AbortFrameRender():Promise<void>;
+
+export function AbortTempUpload(arg1:string):Promise<void>;
+
+export function AppendTempUpload(arg1:string,arg2:string):Promise<void>;
+
+export function BeginFrameRender(arg1:number,arg2:number,arg3:number,arg4:number,arg5:string):Promise<void>;
+
+export function BeginTempUpload(arg1:string,arg2:number):Promise<void>;
+
+export function BuildExportFilename(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function CancelJob(arg1:string):Promise<void>;
+
 export function CheckForUpdates():Promise<autoupdater.UpdateInfo>;
 
-export function ConvertVideoToMP4(arg1:string,arg2:string):Promise<void>;
+export function CommitTempUpload(arg1:string):Promise<void>;
+
+export function ConvertVideoToAPNG(arg1:string,arg2:string,arg3:ffmpeg.APNGOptions,arg4:number):Promise<void>;
+
+export function ConvertVideoToGIF(arg1:string,arg2:string,arg3:ffmpeg.GIFOptions,arg4:number):Promise<void>;
+
+export function ConvertVideoToMP4(arg1:string,arg2:string,arg3:string,arg4:number):Promise<void>;
+
+export function ConvertVideoToWebP(arg1:string,arg2:string,arg3:ffmpeg.WebPOptions,arg4:number):Promise<void>;
+
+export function ConvertVideoWithAlpha(arg1:string,arg2:string,arg3:string,arg4:number):Promise<void>;
 
 export function DeleteGenerator(arg1:string):Promise<void>;
 
@@ -15,14 +40,40 @@ export function DownloadFFmpeg():Promise<void>;
 
 export function DownloadUpdate(arg1:string):Promise<string>;
 
+export function EndFrameRender():Promise<string>;
+
+export function ExportGeneratorBundle(arg1:string):Promise<string>;
+
+export function ExportTo(arg1:string,arg2:string):Promise<string>;
+
 export function GetAppVersion():Promise<string>;
 
+export function GetDefaultAPNGOptions():Promise<ffmpeg.APNGOptions>;
+
+export function GetDefaultGIFOptions():Promise<ffmpeg.GIFOptions>;
+
+export function GetDefaultLoudnessOptions():Promise<ffmpeg.LoudnessOptions>;
+
+export function GetDefaultWebPOptions():Promise<ffmpeg.WebPOptions>;
+
+export function GetEncodeProfiles():Promise<Array<ffmpeg.EncodeProfile>>;
+
+export function GetFFmpegInfo():Promise<ffmpeg.Info>;
+
 export function GetLastSeenVersion():Promise<string>;
 
 export function GetLatestReleaseInfo():Promise<autoupdater.UpdateInfo>;
 
 export function GetPlatformInfo():Promise<Record<string, string>>;
 
+export function GetSettings():Promise<main.Settings>;
+
+export function ImportGenerator(arg1:string,arg2:string,arg3:boolean):Promise<main.ImportReport>;
+
+export function ImportGeneratorBundle(arg1:string,arg2:boolean):Promise<main.ImportReport>;
+
+export function InspectVideo(arg1:string):Promise<ffmpeg.MediaInfo>;
+
 export function InstallUpdate(arg1:string):Promise<void>;
 
 export function InstallUpdateWithRestart(arg1:string):Promise<void>;
@@ -35,6 +86,8 @@ export function IsFirstRunAfterUpdate():Promise<boolean>;
 
 export function IsFirstTimeUser():Promise<boolean>;
 
+export function LinkGeneratorFolder():Promise<main.GeneratorInfo>;
+
 export function ListGenerators():Promise<Array<main.GeneratorInfo>>;
 
 export function LoadGenerator(arg1:string):Promise<string>;
@@ -43,13 +96,29 @@ export function LoadGeneratorConfig(arg1:string):Promise<string>;
 
 export function MarkVersionAsSeen():Promise<void>;
 
-export function ReadTempFile(arg1:string):Promise<Array<number>>;
+export function MixSoundEffects(arg1:string,arg2:string,arg3:Array<ffmpeg.SoundEvent>,arg4:Record<string, ffmpeg.SoundEffect>,arg5:number):Promise<void>;
+
+export function MuxBackgroundMusic(arg1:string,arg2:string,arg3:ffmpeg.AudioTrack,arg4:number):Promise<void>;
+
+export function NormalizeLoudness(arg1:string,arg2:string,arg3:ffmpeg.LoudnessOptions):Promise<ffmpeg.LoudnessReport>;
+
+export function PreviewGeneratorBundle(arg1:string):Promise<main.ImportReport>;
+
+export function PreviewGeneratorImport(arg1:string,arg2:string):Promise<main.ImportReport>;
+
+export function PushFrame(arg1:string):Promise<void>;
 
 export function SaveGenerator(arg1:string,arg2:string):Promise<void>;
 
 export function SaveGeneratorConfig(arg1:string,arg2:string):Promise<void>;
 
-export function SaveTempFile(arg1:string,arg2:Array<number>):Promise<void>;
+export function SaveSettings(arg1:main.Settings):Promise<void>;
+
+export function SelectAudioFile():Promise<string>;
+
+export function SelectOutputDirectory():Promise<string>;
+
+export function SetDefaultEncodeProfile(arg1:string):Promise<void>;
 
 export function SetLastSeenVersion(arg1:string):Promise<void>;
 
@@ -2,12 +2,60 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AbortFrameRender() {
+  return window['go']['main']['App']['AbortFrameRender']();
+}
+
+export function AbortTempUpload(arg1) {
+  return window['go']['main']['App']['AbortTempUpload'](arg1);
+}
+
+export function AppendTempUpload(arg1, arg2) {
+  return window['go']['main']['App']['AppendTempUpload'](arg1, arg2);
+}
+
+export function BeginFrameRender(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['BeginFrameRender'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function BeginTempUpload(arg1, arg2) {
+  return window['go']['main']['App']['BeginTempUpload'](arg1, arg2);
+}
+
+export function BuildExportFilename(arg1, arg2, arg3) {
+  return window['go']['main']['App']['BuildExportFilename'](arg1, arg2, arg3);
+}
+
+export function CancelJob(arg1) {
+  return window['go']['main']['App']['CancelJob'](arg1);
+}
+
 export function CheckForUpdates() {
   return window['go']['main']['App']['CheckForUpdates']();
 }
 
-export function ConvertVideoToMP4(arg1, arg2) {
-  return window['go']['main']['App']['ConvertVideoToMP4'](arg1, arg2);
+export function CommitTempUpload(arg1) {
+  return window['go']['main']['App']['CommitTempUpload'](arg1);
+}
+
+export function ConvertVideoToAPNG(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ConvertVideoToAPNG'](arg1, arg2, arg3, arg4);
+}
+
+export function ConvertVideoToGIF(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ConvertVideoToGIF'](arg1, arg2, arg3, arg4);
+}
+
+export function ConvertVideoToMP4(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ConvertVideoToMP4'](arg1, arg2, arg3, arg4);
+}
+
+export function ConvertVideoToWebP(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ConvertVideoToWebP'](arg1, arg2, arg3, arg4);
+}
+
+export function ConvertVideoWithAlpha(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ConvertVideoWithAlpha'](arg1, arg2, arg3, arg4);
 }
 
 export function DeleteGenerator(arg1) {
@@ -26,10 +74,46 @@ export function DownloadUpdate(arg1) {
   return window['go']['main']['App']['DownloadUpdate'](arg1);
 }
 
+export function EndFrameRender() {
+  return window['go']['main']['App']['EndFrameRender']();
+}
+
+export function ExportGeneratorBundle(arg1) {
+  return window['go']['main']['App']['ExportGeneratorBundle'](arg1);
+}
+
+export function ExportTo(arg1, arg2) {
+  return window['go']['main']['App']['ExportTo'](arg1, arg2);
+}
+
 export function GetAppVersion() {
   return window['go']['main']['App']['GetAppVersion']();
 }
 
+export function GetDefaultAPNGOptions() {
+  return window['go']['main']['App']['GetDefaultAPNGOptions']();
+}
+
+export function GetDefaultGIFOptions() {
+  return window['go']['main']['App']['GetDefaultGIFOptions']();
+}
+
+export function GetDefaultLoudnessOptions() {
+  return window['go']['main']['App']['GetDefaultLoudnessOptions']();
+}
+
+export function GetDefaultWebPOptions() {
+  return window['go']['main']['App']['GetDefaultWebPOptions']();
+}
+
+export function GetEncodeProfiles() {
+  return window['go']['main']['App']['GetEncodeProfiles']();
+}
+
+export function GetFFmpegInfo() {
+  return window['go']['main']['App']['GetFFmpegInfo']();
+}
+
 export function GetLastSeenVersion() {
   return window['go']['main']['App']['GetLastSeenVersion']();
 }
@@ -42,6 +126,22 @@ export function GetPlatformInfo() {
   return window['go']['main']['App']['GetPlatformInfo']();
 }
 
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function ImportGenerator(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportGenerator'](arg1, arg2, arg3);
+}
+
+export function ImportGeneratorBundle(arg1, arg2) {
+  return window['go']['main']['App']['ImportGeneratorBundle'](arg1, arg2);
+}
+
+export function InspectVideo(arg1) {
+  return window['go']['main']['App']['InspectVideo'](arg1);
+}
+
 export function InstallUpdate(arg1) {
   return window['go']['main']['App']['InstallUpdate'](arg1);
 }
@@ -66,6 +166,10 @@ export function IsFirstTimeUser() {
   return window['go']['main']['App']['IsFirstTimeUser']();
 }
 
+export function LinkGeneratorFolder() {
+  return window['go']['main']['App']['LinkGeneratorFolder']();
+}
+
 export function ListGenerators() {
   return window['go']['main']['App']['ListGenerators']();
 }
@@ -82,8 +186,28 @@ export function MarkVersionAsSeen() {
   return window['go']['main']['App']['MarkVersionAsSeen']();
 }
 
-export function ReadTempFile(arg1) {
-  return window['go']['main']['App']['ReadTempFile'](arg1);
+export function MixSoundEffects(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['MixSoundEffects'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function MuxBackgroundMusic(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['MuxBackgroundMusic'](arg1, arg2, arg3, arg4);
+}
+
+export function NormalizeLoudness(arg1, arg2, arg3) {
+  return window['go']['main']['App']['NormalizeLoudness'](arg1, arg2, arg3);
+}
+
+export function PreviewGeneratorBundle(arg1) {
+  return window['go']['main']['App']['PreviewGeneratorBundle'](arg1);
+}
+
+export function PreviewGeneratorImport(arg1, arg2) {
+  return window['go']['main']['App']['PreviewGeneratorImport'](arg1, arg2);
+}
+
+export function PushFrame(arg1) {
+  return window['go']['main']['App']['PushFrame'](arg1);
 }
 
 export function SaveGenerator(arg1, arg2) {
@@ -94,8 +218,20 @@ export function SaveGeneratorConfig(arg1, arg2) {
   return window['go']['main']['App']['SaveGeneratorConfig'](arg1, arg2);
 }
 
-export function SaveTempFile(arg1, arg2) {
-  return window['go']['main']['App']['SaveTempFile'](arg1, arg2);
+export function SaveSettings(arg1) {
+  return window['go']['main']['App']['SaveSettings'](arg1);
+}
+
+export function SelectAudioFile() {
+  return window['go']['main']['App']['SelectAudioFile']();
+}
+
+export function SelectOutputDirectory() {
+  return window['go']['main']['App']['SelectOutputDirectory']();
+}
+
+export function SetDefaultEncodeProfile(arg1) {
+  return window['go']['main']['App']['SetDefaultEncodeProfile'](arg1);
 }
 
 export function SetLastSeenVersion(arg1) {
@@ -27,6 +27,271 @@ export namespace autoupdater {
 
 }
 
+export namespace ffmpeg {
+	
+	export class APNGOptions {
+	    loop: number;
+	    width: number;
+	    fps: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new APNGOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.loop = source["loop"];
+	        this.width = source["width"];
+	        this.fps = source["fps"];
+	    }
+	}
+	export class AudioTrack {
+	    path: string;
+	    startOffset: number;
+	    trimStart: number;
+	    trimEnd: number;
+	    fadeIn: number;
+	    fadeOut: number;
+	    volume: number;
+	    loop: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AudioTrack(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.startOffset = source["startOffset"];
+	        this.trimStart = source["trimStart"];
+	        this.trimEnd = source["trimEnd"];
+	        this.fadeIn = source["fadeIn"];
+	        this.fadeOut = source["fadeOut"];
+	        this.volume = source["volume"];
+	        this.loop = source["loop"];
+	    }
+	}
+	export class EncodeOptions {
+	    videoCodec: string;
+	    crf: number;
+	    bitrate: string;
+	    preset: string;
+	    pixelFormat: string;
+	    keyframeInterval: number;
+	    tune: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EncodeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.videoCodec = source["videoCodec"];
+	        this.crf = source["crf"];
+	        this.bitrate = source["bitrate"];
+	        this.preset = source["preset"];
+	        this.pixelFormat = source["pixelFormat"];
+	        this.keyframeInterval = source["keyframeInterval"];
+	        this.tune = source["tune"];
+	    }
+	}
+	export class EncodeProfile {
+	    name: string;
+	    label: string;
+	    options: EncodeOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new EncodeProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.options = this.convertValues(source["options"], EncodeOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GIFOptions {
+	    width: number;
+	    fps: number;
+	    dither: string;
+	    loop: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GIFOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.width = source["width"];
+	        this.fps = source["fps"];
+	        this.dither = source["dither"];
+	        this.loop = source["loop"];
+	    }
+	}
+	export class Info {
+	    path: string;
+	    source: string;
+	    version: string;
+	    encoders: string[];
+	    warning?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.source = source["source"];
+	        this.version = source["version"];
+	        this.encoders = source["encoders"];
+	        this.warning = source["warning"];
+	    }
+	}
+	export class LoudnessOptions {
+	    targetLufs: number;
+	    truePeak: number;
+	    lra: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoudnessOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.targetLufs = source["targetLufs"];
+	        this.truePeak = source["truePeak"];
+	        this.lra = source["lra"];
+	    }
+	}
+	export class LoudnessReport {
+	    inputI: number;
+	    inputTp: number;
+	    inputLra: number;
+	    outputI: number;
+	    outputTp: number;
+	    outputLra: number;
+	    normalizationType: string;
+	    target: LoudnessOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoudnessReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputI = source["inputI"];
+	        this.inputTp = source["inputTp"];
+	        this.inputLra = source["inputLra"];
+	        this.outputI = source["outputI"];
+	        this.outputTp = source["outputTp"];
+	        this.outputLra = source["outputLra"];
+	        this.normalizationType = source["normalizationType"];
+	        this.target = this.convertValues(source["target"], LoudnessOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MediaInfo {
+	    duration: number;
+	    width: number;
+	    height: number;
+	    frameRate: number;
+	    codec: string;
+	    pixelFormat: string;
+	    audioCodec: string;
+	    bitrate: number;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MediaInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.duration = source["duration"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.frameRate = source["frameRate"];
+	        this.codec = source["codec"];
+	        this.pixelFormat = source["pixelFormat"];
+	        this.audioCodec = source["audioCodec"];
+	        this.bitrate = source["bitrate"];
+	        this.size = source["size"];
+	    }
+	}
+	export class SoundEvent {
+	    name: string;
+	    time: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SoundEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.time = source["time"];
+	    }
+	}
+	export class WebPOptions {
+	    lossless: boolean;
+	    quality: number;
+	    loop: number;
+	    width: number;
+	    fps: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebPOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lossless = source["lossless"];
+	        this.quality = source["quality"];
+	        this.loop = source["loop"];
+	        this.width = source["width"];
+	        this.fps = source["fps"];
+	    }
+	}
+
+}
+
 export namespace main {
 	
 	export class ChangelogResult {
@@ -48,6 +313,15 @@ export namespace main {
 	export class GeneratorInfo {
 	    name: string;
 	    filename: string;
+	    version: string;
+	    packageId: string;
+	    apiVersion: string;
+	    author: string;
+	    description: string;
+	    config: manifest.ConfigParam[];
+	    parseError?: string;
+	    bundle: boolean;
+	    linked: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new GeneratorInfo(source);
@@ -57,7 +331,139 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
 	        this.filename = source["filename"];
+	        this.version = source["version"];
+	        this.packageId = source["packageId"];
+	        this.apiVersion = source["apiVersion"];
+	        this.author = source["author"];
+	        this.description = source["description"];
+	        this.config = this.convertValues(source["config"], manifest.ConfigParam);
+	        this.parseError = source["parseError"];
+	        this.bundle = source["bundle"];
+	        this.linked = source["linked"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportReport {
+	    action: string;
+	    packageId: string;
+	    name: string;
+	    version: string;
+	    installedVersion: string;
+	    filename: string;
+	    conflicts: string[];
+	    duplicates: string[];
+	    droppedParams: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.packageId = source["packageId"];
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.installedVersion = source["installedVersion"];
+	        this.filename = source["filename"];
+	        this.conflicts = source["conflicts"];
+	        this.duplicates = source["duplicates"];
+	        this.droppedParams = source["droppedParams"];
+	    }
+	}
+	export class Settings {
+	    defaultProfile: string;
+	    ffmpegPath: string;
+	    outputDir: string;
+	    filenameTemplate: string;
+	    tempMaxAgeHours: number;
+	    tempMaxSizeMB: number;
+	    linkedGenerators: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.defaultProfile = source["defaultProfile"];
+	        this.ffmpegPath = source["ffmpegPath"];
+	        this.outputDir = source["outputDir"];
+	        this.filenameTemplate = source["filenameTemplate"];
+	        this.tempMaxAgeHours = source["tempMaxAgeHours"];
+	        this.tempMaxSizeMB = source["tempMaxSizeMB"];
+	        this.linkedGenerators = source["linkedGenerators"];
+	    }
+	}
+
+}
+
+export namespace manifest {
+	
+	export class ConfigParam {
+	    type: string;
+	    name: string;
+	    label?: string;
+	    default?: any;
+	    min?: number;
+	    max?: number;
+	    step?: number;
+	    depend_on?: string;
+	    collapse?: boolean;
+	    content?: ConfigParam[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigParam(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.default = source["default"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.step = source["step"];
+	        this.depend_on = source["depend_on"];
+	        this.collapse = source["collapse"];
+	        this.content = this.convertValues(source["content"], ConfigParam);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
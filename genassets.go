@@ -0,0 +1,121 @@
+package main
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"VibeCraft/pkg/safepath"
+)
+
+// generatorAssetsURLPrefix est la route qui sert les ressources d'un
+// générateur au format bundle, installé ou lié :
+// /gen-assets/<package_id>/<chemin>.
+const generatorAssetsURLPrefix = "/gen-assets/"
+
+// Types MIME des ressources courantes, sans dépendre de la table du système
+// (souvent incomplète sous Windows pour les polices et l'audio).
+var assetMimeTypes = map[string]string{
+	".png":   "image/png",
+	".jpg":   "image/jpeg",
+	".jpeg":  "image/jpeg",
+	".gif":   "image/gif",
+	".webp":  "image/webp",
+	".avif":  "image/avif",
+	".svg":   "image/svg+xml",
+	".ico":   "image/x-icon",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+	".mp3":   "audio/mpeg",
+	".wav":   "audio/wav",
+	".ogg":   "audio/ogg",
+	".m4a":   "audio/mp4",
+	".mp4":   "video/mp4",
+	".webm":  "video/webm",
+	".json":  "application/json",
+	".txt":   "text/plain; charset=utf-8",
+	".js":    "text/javascript; charset=utf-8",
+	".css":   "text/css; charset=utf-8",
+}
+
+func assetContentType(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if contentType, ok := assetMimeTypes[ext]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// generatorDir renvoie le dossier d'un générateur au format bundle, installé
+// ou lié, à partir de son package_id.
+func (a *App) generatorDir(packageID string) (string, bool) {
+	if !packageIDRegex.MatchString(packageID) {
+		return "", false
+	}
+	if dir, ok := a.linkedGenerators()[packageID]; ok {
+		return dir, true
+	}
+	dir := filepath.Join(a.getGeneratorsDir(), packageID)
+	if _, err := os.Stat(filepath.Join(dir, bundleManifestName)); err != nil {
+		return "", false
+	}
+	return dir, true
+}
+
+// generatorAssetsHandler sert les fichiers du dossier d'un générateur, pour
+// qu'il puisse charger images et polices (new Image(), FontFace). Ces
+// ressources ayant la même origine que l'application, le canvas qui les
+// dessine reste exportable.
+func (a *App) generatorAssetsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "méthode non autorisée", http.StatusMethodNotAllowed)
+		return
+	}
+
+	packageID, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, generatorAssetsURLPrefix), "/")
+	dir, ok := a.generatorDir(packageID)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	filePath, err := safepath.Resolve(dir, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	// Comme à l'export en bundle, les fichiers cachés (.git, .env) et
+	// node_modules d'un dossier de développement ne sont pas servis
+	if rel, ok := relativeTo(dir, filePath); !ok || ignoredPath(rel) {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Une ressource ouverte directement (SVG, HTML) ne doit pas pouvoir
+	// exécuter de script dans la fenêtre de l'application
+	w.Header().Set("Content-Type", assetContentType(stat.Name()))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox; default-src 'none'; style-src 'unsafe-inline'; img-src data:")
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, stat.Name(), stat.ModTime(), file)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGeneratorAssetsHandlerLinkedFolder(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	dir := filepath.Join(t.TempDir(), "spirale")
+	files := map[string]string{
+		bundleManifestName:          `{"name": "Spirale", "version": "1.0.0", "package_id": "com.exemple.spirale"}`,
+		"index.js":                  "class Spirale {}",
+		"assets/logo.svg":           "<svg/>",
+		".env":                      "TOKEN=secret",
+		".git/config":               "[core]",
+		"assets/.cache/frame.png":   "png",
+		"node_modules/lib/index.js": "module.exports = {}",
+		"assets/logo.svg~":          "<svg/>",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	settings := defaultSettings()
+	settings.LinkedGenerators = []string{dir}
+	data, _ := json.Marshal(settings)
+	app := NewApp()
+	if err := os.MkdirAll(filepath.Dir(app.getSettingsPath()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(app.getSettingsPath(), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"com.exemple.spirale/index.js", http.StatusOK},
+		{"com.exemple.spirale/assets/logo.svg", http.StatusOK},
+		{"com.exemple.spirale/.env", http.StatusNotFound},
+		{"com.exemple.spirale/.git/config", http.StatusNotFound},
+		{"com.exemple.spirale/assets/.cache/frame.png", http.StatusNotFound},
+		{"com.exemple.spirale/node_modules/lib/index.js", http.StatusNotFound},
+		{"com.exemple.spirale/assets/logo.svg~", http.StatusNotFound},
+		{"com.exemple.spirale/absent.png", http.StatusNotFound},
+		{"com.exemple.inconnu/index.js", http.StatusNotFound},
+		{"com.exemple.spirale/assets/../../secret", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = generatorAssetsURLPrefix + tt.path
+		rec := httptest.NewRecorder()
+		app.generatorAssetsHandler(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("GET %s = %d ; attendu %d", tt.path, rec.Code, tt.want)
+		}
+	}
+}
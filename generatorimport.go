@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"VibeCraft/pkg/manifest"
+	"VibeCraft/pkg/safepath"
+)
+
+const (
+	ImportInstall   = "install"
+	ImportUpgrade   = "upgrade"
+	ImportDowngrade = "downgrade"
+	ImportSame      = "same"
+)
+
+// ImportReport décrit l'effet d'un import avant (PreviewGeneratorImport) ou
+// après (ImportGenerator) son application.
+type ImportReport struct {
+	Action           string `json:"action"`
+	PackageID        string `json:"packageId"`
+	Name             string `json:"name"`
+	Version          string `json:"version"`
+	InstalledVersion string `json:"installedVersion"`
+	// Filename est le fichier écrit : celui déjà installé en cas de mise à
+	// jour, pour que le générateur garde son identifiant dans l'interface.
+	Filename string `json:"filename"`
+	// Conflicts liste les problèmes qui empêchent l'import.
+	Conflicts []string `json:"conflicts"`
+	// Duplicates sont les autres fichiers du même package_id, supprimés à l'import.
+	Duplicates []string `json:"duplicates"`
+	// DroppedParams sont les réglages enregistrés qui n'existent plus dans la
+	// nouvelle version.
+	DroppedParams []string `json:"droppedParams"`
+}
+
+func (a *App) PreviewGeneratorImport(filename, content string) (*ImportReport, error) {
+	m, err := manifest.Extract(content)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(filename) != ".js" {
+		filename += ".js"
+	}
+	if _, err := safepath.Resolve(a.getGeneratorsDir(), filename); err != nil {
+		return nil, err
+	}
+
+	report, err := a.planImport(m, filename)
+	if err != nil {
+		return nil, err
+	}
+	// Écrire le script seul dans un bundle laisserait son manifest.json (et
+	// donc la version listée) inchangé : le bundle se met à jour par un .vcgen
+	for _, name := range append([]string{report.Filename}, report.Duplicates...) {
+		if a.bundleDir(name) != "" {
+			report.Conflicts = append(report.Conflicts,
+				fmt.Sprintf("%s est installé en bundle (%s) : importez un fichier .vcgen pour le mettre à jour", m.PackageID, name))
+		}
+	}
+	return report, nil
+}
+
+// planImport compare le manifest importé aux générateurs installés.
+func (a *App) planImport(m *manifest.Manifest, filename string) (*ImportReport, error) {
+	generators, err := a.ListGenerators()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Action:    ImportInstall,
+		PackageID: m.PackageID,
+		Name:      m.Name,
+		Version:   m.Version,
+		Filename:  filename,
+	}
+
+	// Un dossier lié appartient au développeur : l'import n'y écrit jamais
+	var linkedConflict string
+	var installed []GeneratorInfo
+	for _, g := range generators {
+		if g.Linked {
+			if g.PackageID == m.PackageID {
+				linkedConflict = fmt.Sprintf("%s est lié au dossier de développement %s", m.PackageID, a.linkedGenerators()[m.PackageID])
+			}
+			continue
+		}
+		if g.PackageID == m.PackageID {
+			installed = append(installed, g)
+		} else if g.Filename == filename {
+			report.Conflicts = append(report.Conflicts,
+				fmt.Sprintf("le fichier %s est déjà utilisé par %s (%s)", filename, g.Name, packageLabel(g)))
+		}
+	}
+
+	if linkedConflict != "" {
+		report.Conflicts = append(report.Conflicts, linkedConflict)
+		return report, nil
+	}
+
+	if len(installed) == 0 {
+		return report, nil
+	}
+
+	// Plusieurs fichiers pour un même paquet : on garde le plus récent
+	sort.SliceStable(installed, func(i, j int) bool {
+		cmp, err := manifest.CompareVersions(installed[i].Version, installed[j].Version)
+		return err == nil && cmp > 0
+	})
+	current := installed[0]
+	for _, g := range installed[1:] {
+		report.Duplicates = append(report.Duplicates, g.Filename)
+	}
+
+	report.InstalledVersion = current.Version
+	report.Filename = current.Filename
+	// Le fichier d'origine est remplacé : plus de conflit de nom possible
+	report.Conflicts = nil
+
+	cmp, err := manifest.CompareVersions(m.Version, current.Version)
+	if err != nil {
+		report.Conflicts = append(report.Conflicts, fmt.Sprintf("comparaison de versions impossible: %v", err))
+		return report, nil
+	}
+	switch {
+	case cmp > 0:
+		report.Action = ImportUpgrade
+	case cmp < 0:
+		report.Action = ImportDowngrade
+	default:
+		report.Action = ImportSame
+	}
+
+	return report, nil
+}
+
+func packageLabel(g GeneratorInfo) string {
+	if g.PackageID == "" {
+		return "manifest illisible"
+	}
+	return g.PackageID
+}
+
+// ImportGenerator installe un générateur en l'identifiant par son
+// package_id : une version déjà installée est remplacée sur place et ses
+// réglages enregistrés sont adaptés au nouveau schéma. Un retour à une
+// version antérieure doit être autorisé explicitement.
+func (a *App) ImportGenerator(filename, content string, allowDowngrade bool) (*ImportReport, error) {
+	report, err := a.PreviewGeneratorImport(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImport(report, allowDowngrade); err != nil {
+		return report, err
+	}
+
+	if err := a.SaveGenerator(report.Filename, content); err != nil {
+		return report, fmt.Errorf("erreur écriture %s: %w", report.Filename, err)
+	}
+
+	m, _ := manifest.Extract(content)
+	if err := a.finishImport(report, m.Config); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func checkImport(report *ImportReport, allowDowngrade bool) error {
+	if len(report.Conflicts) > 0 {
+		return fmt.Errorf("import impossible: %s", strings.Join(report.Conflicts, "; "))
+	}
+	if report.Action == ImportDowngrade && !allowDowngrade {
+		return fmt.Errorf("version %s plus ancienne que la version installée %s", report.Version, report.InstalledVersion)
+	}
+	return nil
+}
+
+// finishImport supprime les doublons et migre les réglages une fois le
+// nouveau générateur écrit.
+func (a *App) finishImport(report *ImportReport, config []manifest.ConfigParam) error {
+	for _, duplicate := range report.Duplicates {
+		a.DeleteGenerator(duplicate)
+	}
+
+	if report.Action != ImportInstall {
+		dropped, err := a.migrateGeneratorConfig(report.PackageID, config)
+		if err != nil {
+			return fmt.Errorf("générateur importé mais réglages non migrés: %w", err)
+		}
+		report.DroppedParams = dropped
+	}
+	return nil
+}
+
+// migrateGeneratorConfig retire des réglages enregistrés les paramètres qui
+// n'existent plus ou dont le type a changé. Les nouveaux paramètres prendront
+// leur valeur par défaut au chargement.
+func (a *App) migrateGeneratorConfig(packageID string, config []manifest.ConfigParam) ([]string, error) {
+	raw, err := a.LoadGeneratorConfig(packageID)
+	if err != nil || raw == "" {
+		return nil, err
+	}
+
+	var saved map[string]any
+	if err := json.Unmarshal([]byte(raw), &saved); err != nil {
+		return nil, fmt.Errorf("réglages enregistrés illisibles: %w", err)
+	}
+
+	params := make(map[string]manifest.ConfigParam)
+	for _, param := range manifest.FlattenConfig(config) {
+		params[param.Name] = param
+	}
+
+	var dropped []string
+	for name, value := range saved {
+		param, ok := params[name]
+		if !ok || !configValueMatches(param.Type, value) {
+			delete(saved, name)
+			dropped = append(dropped, name)
+		}
+	}
+	if len(dropped) == 0 {
+		return nil, nil
+	}
+	sort.Strings(dropped)
+
+	out, err := json.Marshal(saved)
+	if err != nil {
+		return nil, err
+	}
+	return dropped, a.SaveGeneratorConfig(packageID, string(out))
+}
+
+func configValueMatches(paramType string, value any) bool {
+	switch paramType {
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "color":
+		_, ok := value.(string)
+		return ok
+	}
+	return true
+}
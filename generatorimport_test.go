@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"VibeCraft/pkg/manifest"
+)
+
+func generatorSource(packageID, version string) string {
+	return fmt.Sprintf(`const MANIFEST = { name: "Test", version: %q, package_id: %q, config: [] };
+class Test {}`, version, packageID)
+}
+
+func newImportTestApp(t *testing.T, installed map[string]string) *App {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	app := NewApp()
+	if err := os.MkdirAll(app.getGeneratorsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for filename, content := range installed {
+		if err := app.SaveGenerator(filename, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return app
+}
+
+func TestPlanImport(t *testing.T) {
+	tests := []struct {
+		name       string
+		installed  map[string]string
+		filename   string
+		version    string
+		action     string
+		target     string
+		duplicates []string
+		conflict   bool
+	}{
+		{
+			name:     "nouveau paquet",
+			filename: "spirale.js",
+			version:  "1.0.0",
+			action:   ImportInstall,
+			target:   "spirale.js",
+		},
+		{
+			name:      "mise à jour sous un autre nom",
+			installed: map[string]string{"ancien.js": generatorSource("com.exemple.spirale", "1.0.0")},
+			filename:  "spirale.js",
+			version:   "1.2.0",
+			action:    ImportUpgrade,
+			target:    "ancien.js",
+		},
+		{
+			name:      "version antérieure",
+			installed: map[string]string{"spirale.js": generatorSource("com.exemple.spirale", "2.0.0")},
+			filename:  "spirale.js",
+			version:   "1.9.9",
+			action:    ImportDowngrade,
+			target:    "spirale.js",
+		},
+		{
+			name:      "même version",
+			installed: map[string]string{"spirale.js": generatorSource("com.exemple.spirale", "v1.0.0")},
+			filename:  "spirale.js",
+			version:   "1.0.0",
+			action:    ImportSame,
+			target:    "spirale.js",
+		},
+		{
+			name: "doublons",
+			installed: map[string]string{
+				"spirale-1.0.js": generatorSource("com.exemple.spirale", "1.0.0"),
+				"spirale-1.1.js": generatorSource("com.exemple.spirale", "1.1.0"),
+			},
+			filename:   "spirale.js",
+			version:    "2.0.0",
+			action:     ImportUpgrade,
+			target:     "spirale-1.1.js",
+			duplicates: []string{"spirale-1.0.js"},
+		},
+		{
+			name:      "nom déjà pris par un autre paquet",
+			installed: map[string]string{"spirale.js": generatorSource("com.exemple.autre", "1.0.0")},
+			filename:  "spirale.js",
+			version:   "1.0.0",
+			action:    ImportInstall,
+			target:    "spirale.js",
+			conflict:  true,
+		},
+		{
+			name:      "version installée invalide",
+			installed: map[string]string{"spirale.js": generatorSource("com.exemple.spirale", "latest")},
+			filename:  "spirale.js",
+			version:   "1.0.0",
+			action:    ImportInstall,
+			target:    "spirale.js",
+			conflict:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		app := newImportTestApp(t, tt.installed)
+		report, err := app.PreviewGeneratorImport(tt.filename, generatorSource("com.exemple.spirale", tt.version))
+		if err != nil {
+			t.Errorf("%s : %v", tt.name, err)
+			continue
+		}
+		if report.Action != tt.action || report.Filename != tt.target {
+			t.Errorf("%s : action %q vers %q ; attendu %q vers %q", tt.name, report.Action, report.Filename, tt.action, tt.target)
+		}
+		if !reflect.DeepEqual(report.Duplicates, tt.duplicates) {
+			t.Errorf("%s : doublons %v ; attendu %v", tt.name, report.Duplicates, tt.duplicates)
+		}
+		if (len(report.Conflicts) > 0) != tt.conflict {
+			t.Errorf("%s : conflits %v", tt.name, report.Conflicts)
+		}
+	}
+}
+
+func TestImportGeneratorRefusesDowngrade(t *testing.T) {
+	app := newImportTestApp(t, map[string]string{"spirale.js": generatorSource("com.exemple.spirale", "2.0.0")})
+	older := generatorSource("com.exemple.spirale", "1.0.0")
+
+	if _, err := app.ImportGenerator("spirale.js", older, false); err == nil {
+		t.Fatal("retour à une version antérieure accepté sans autorisation")
+	}
+	if _, err := app.ImportGenerator("spirale.js", older, true); err != nil {
+		t.Fatalf("retour autorisé refusé : %v", err)
+	}
+	content, _ := app.LoadGenerator("spirale.js")
+	if content != older {
+		t.Errorf("spirale.js non remplacé")
+	}
+}
+
+func TestMigrateGeneratorConfig(t *testing.T) {
+	app := newImportTestApp(t, nil)
+	saved := `{"speed": 3, "ballColor": "#ff0000", "isFilled": "oui", "ancien": 1, "label": null}`
+	if err := app.SaveGeneratorConfig("com.exemple.spirale", saved); err != nil {
+		t.Fatal(err)
+	}
+
+	config := []manifest.ConfigParam{
+		{Type: "number", Name: "speed"},
+		{Type: "color", Name: "ballColor"},
+		{Type: "categorie", Name: "Apparence", Content: []manifest.ConfigParam{
+			{Type: "boolean", Name: "isFilled"},
+			{Type: "text", Name: "label"},
+		}},
+	}
+	dropped, err := app.migrateGeneratorConfig("com.exemple.spirale", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ancien", "isFilled"}; !reflect.DeepEqual(dropped, want) {
+		t.Errorf("paramètres retirés %v ; attendu %v", dropped, want)
+	}
+
+	raw, _ := app.LoadGeneratorConfig("com.exemple.spirale")
+	var got map[string]any
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"speed": 3.0, "ballColor": "#ff0000", "label": nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("réglages migrés %v ; attendu %v", got, want)
+	}
+
+	// Rien à retirer : les réglages restent tels quels
+	if dropped, err := app.migrateGeneratorConfig("com.exemple.spirale", config); err != nil || dropped != nil {
+		t.Errorf("seconde migration : %v, %v", dropped, err)
+	}
+}
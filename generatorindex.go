@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// generatorIndexVersion est incrémenté quand GeneratorInfo change de forme,
+// pour forcer la relecture de tous les manifests.
+const generatorIndexVersion = 4
+
+type generatorIndexEntry struct {
+	Size    int64         `json:"size"`
+	ModTime int64         `json:"modTime"`
+	Info    GeneratorInfo `json:"info"`
+}
+
+type generatorIndexFile struct {
+	Version int                            `json:"version"`
+	Entries map[string]generatorIndexEntry `json:"entries"`
+}
+
+// generatorIndex garde sur disque les manifests déjà lus, indexés par nom de
+// fichier. Une entrée n'est réutilisée que si la taille et la date de
+// modification du fichier n'ont pas changé.
+type generatorIndex struct {
+	mu      sync.Mutex
+	loaded  bool
+	dirty   bool
+	entries map[string]generatorIndexEntry
+}
+
+func newGeneratorIndex() *generatorIndex {
+	return &generatorIndex{entries: make(map[string]generatorIndexEntry)}
+}
+
+func (a *App) getGeneratorIndexPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".vibecraft", "generators-index.json")
+}
+
+// load lit l'index au premier accès. Un index absent, illisible ou d'une
+// autre version est simplement reconstruit.
+func (idx *generatorIndex) load(path string) {
+	if idx.loaded {
+		return
+	}
+	idx.loaded = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var file generatorIndexFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != generatorIndexVersion {
+		return
+	}
+	if file.Entries != nil {
+		idx.entries = file.Entries
+	}
+}
+
+func (idx *generatorIndex) save(path string) error {
+	if !idx.dirty {
+		return nil
+	}
+
+	out, err := json.Marshal(generatorIndexFile{Version: generatorIndexVersion, Entries: idx.entries})
+	if err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+func (idx *generatorIndex) lookup(filename string, info os.FileInfo) (GeneratorInfo, bool) {
+	entry, ok := idx.entries[filename]
+	if !ok || entry.Size != info.Size() || entry.ModTime != info.ModTime().UnixNano() {
+		return GeneratorInfo{}, false
+	}
+	return entry.Info, true
+}
+
+func (idx *generatorIndex) store(filename string, info os.FileInfo, generator GeneratorInfo) {
+	idx.entries[filename] = generatorIndexEntry{
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		Info:    generator,
+	}
+	idx.dirty = true
+}
+
+func (idx *generatorIndex) remove(filename string) {
+	if _, ok := idx.entries[filename]; ok {
+		delete(idx.entries, filename)
+		idx.dirty = true
+	}
+}
+
+// prune oublie les fichiers qui ne sont plus dans le dossier.
+func (idx *generatorIndex) prune(present map[string]bool) {
+	for filename := range idx.entries {
+		if !present[filename] {
+			delete(idx.entries, filename)
+			idx.dirty = true
+		}
+	}
+}
+
+// invalidateGenerator retire un générateur de l'index après une écriture ou
+// une suppression.
+func (a *App) invalidateGenerator(filename string) {
+	a.generatorIndex.mu.Lock()
+	defer a.generatorIndex.mu.Unlock()
+
+	path := a.getGeneratorIndexPath()
+	a.generatorIndex.load(path)
+	a.generatorIndex.remove(filename)
+	a.generatorIndex.save(path)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"VibeCraft/pkg/safepath"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Un dossier de développement lié est un générateur au format bundle
+// (manifest.json, script d'entrée, assets/) lu en place, hors de
+// ~/.vibecraft/generators. Il est désigné comme un bundle installé, par
+// <package_id>/<entry>, et prend le pas sur lui.
+
+// linkedGenerators renvoie les dossiers liés dont le manifest.json est
+// lisible, indexés par package_id.
+func (a *App) linkedGenerators() map[string]string {
+	linked := make(map[string]string)
+	for _, dir := range a.GetSettings().LinkedGenerators {
+		m, err := readBundleManifest(dir)
+		if err != nil {
+			continue
+		}
+		if _, ok := linked[m.PackageID]; !ok {
+			linked[m.PackageID] = dir
+		}
+	}
+	return linked
+}
+
+// resolveGeneratorPath traduit un nom de générateur en chemin sur disque,
+// dans un dossier lié ou dans ~/.vibecraft/generators.
+func (a *App) resolveGeneratorPath(filename string) (string, error) {
+	if packageID, rest, found := strings.Cut(filepath.ToSlash(filename), "/"); found {
+		if dir, ok := a.linkedGenerators()[packageID]; ok {
+			return safepath.Resolve(dir, rest)
+		}
+	}
+	return safepath.Resolve(a.getGeneratorsDir(), filename)
+}
+
+// listLinkedGenerators lit les générateurs des dossiers liés, sans passer par
+// l'index : ils sont peu nombreux et modifiés en permanence.
+func (a *App) listLinkedGenerators() []GeneratorInfo {
+	linked := a.linkedGenerators()
+	packageIDs := make([]string, 0, len(linked))
+	for packageID := range linked {
+		packageIDs = append(packageIDs, packageID)
+	}
+	slices.Sort(packageIDs)
+
+	generators := make([]GeneratorInfo, 0, len(linked))
+	for _, packageID := range packageIDs {
+		generators = append(generators, readBundleInfo(linked[packageID], packageID, true))
+	}
+	return generators
+}
+
+// LinkGeneratorFolder demande un dossier de développement et l'ajoute aux
+// générateurs, qui le relisent à chaque modification. Renvoie nil si
+// l'utilisateur a annulé.
+func (a *App) LinkGeneratorFolder() (*GeneratorInfo, error) {
+	dir, err := wailsruntime.OpenDirectoryDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title: "Choisir le dossier du générateur",
+	})
+	if err != nil || dir == "" {
+		return nil, err
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return nil, err
+	}
+
+	m, err := readBundleManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("dossier de générateur invalide: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(m.Entry))); err != nil {
+		return nil, fmt.Errorf("script d'entrée %s introuvable", m.Entry)
+	}
+	if linkedDir, ok := a.linkedGenerators()[m.PackageID]; ok {
+		return nil, fmt.Errorf("%s est déjà lié depuis %s", m.PackageID, linkedDir)
+	}
+	if _, err := os.Stat(filepath.Join(a.getGeneratorsDir(), m.PackageID)); err == nil {
+		return nil, fmt.Errorf("%s est déjà installé : supprimez-le avant de lier son dossier", m.PackageID)
+	}
+
+	settings := a.GetSettings()
+	settings.LinkedGenerators = append(settings.LinkedGenerators, dir)
+	if err := a.SaveSettings(settings); err != nil {
+		return nil, err
+	}
+
+	if a.watcher != nil {
+		a.watcher.addLinked(dir)
+	}
+	info := readBundleInfo(dir, m.PackageID, true)
+	return &info, nil
+}
+
+// unlinkGenerator retire un dossier lié sans toucher à son contenu.
+func (a *App) unlinkGenerator(dir string) error {
+	settings := a.GetSettings()
+	settings.LinkedGenerators = slices.DeleteFunc(settings.LinkedGenerators, func(linked string) bool {
+		return linked == dir
+	})
+	if err := a.SaveSettings(settings); err != nil {
+		return err
+	}
+
+	if a.watcher != nil {
+		a.watcher.removeLinked(dir)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Les éditeurs enregistrent souvent en plusieurs écritures (fichier
+// temporaire, renommage) : on attend que le fichier soit stable.
+const generatorReloadDelay = 200 * time.Millisecond
+
+// GeneratorEvent accompagne les événements generator-changed et
+// generator-removed.
+type GeneratorEvent struct {
+	Filename  string         `json:"filename"`
+	Generator *GeneratorInfo `json:"generator,omitempty"`
+}
+
+// generatorWatcher surveille ~/.vibecraft/generators et les dossiers liés.
+// Chaque générateur est identifié par son chemin sur disque : le fichier .js,
+// ou le dossier d'un bundle dont n'importe quel fichier peut changer.
+type generatorWatcher struct {
+	app           *App
+	fs            *fsnotify.Watcher
+	generatorsDir string
+
+	mu      sync.Mutex
+	linked  map[string]bool
+	known   map[string]string // chemin -> nom du générateur
+	pending map[string]*time.Timer
+}
+
+func (a *App) startGeneratorWatcher() {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		wailsruntime.LogWarningf(a.ctx, "rechargement automatique des générateurs indisponible: %v", err)
+		return
+	}
+
+	w := &generatorWatcher{
+		app:           a,
+		fs:            fsWatcher,
+		generatorsDir: a.getGeneratorsDir(),
+		linked:        make(map[string]bool),
+		known:         make(map[string]string),
+		pending:       make(map[string]*time.Timer),
+	}
+
+	w.mu.Lock()
+	w.addTree(w.generatorsDir)
+	if entries, err := os.ReadDir(w.generatorsDir); err == nil {
+		for _, entry := range entries {
+			key := w.keyFor(filepath.Join(w.generatorsDir, entry.Name()))
+			switch {
+			case key == "":
+			case entry.IsDir():
+				w.refresh(key)
+			default:
+				// Le nom d'un fichier .js suffit, inutile de relire son manifest
+				w.known[key] = entry.Name()
+			}
+		}
+	}
+	w.mu.Unlock()
+
+	for _, dir := range a.GetSettings().LinkedGenerators {
+		w.addLinked(dir)
+	}
+
+	a.watcher = w
+	go w.run()
+}
+
+func (w *generatorWatcher) close() {
+	w.mu.Lock()
+	for _, timer := range w.pending {
+		timer.Stop()
+	}
+	w.mu.Unlock()
+	w.fs.Close()
+}
+
+func (w *generatorWatcher) run() {
+	for {
+		select {
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			w.handle(event)
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			wailsruntime.LogWarningf(w.app.ctx, "surveillance des générateurs: %v", err)
+		}
+	}
+}
+
+func (w *generatorWatcher) handle(event fsnotify.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// fsnotify n'est pas récursif : les nouveaux dossiers (bundle importé,
+	// sous-dossier d'assets) sont ajoutés au fil de l'eau
+	if event.Has(fsnotify.Create) {
+		if stat, err := os.Stat(event.Name); err == nil && stat.IsDir() {
+			w.addTree(event.Name)
+		}
+	}
+
+	key := w.keyFor(event.Name)
+	if key == "" {
+		return
+	}
+	if timer, ok := w.pending[key]; ok {
+		timer.Reset(generatorReloadDelay)
+		return
+	}
+	w.pending[key] = time.AfterFunc(generatorReloadDelay, func() { w.flush(key) })
+}
+
+// keyFor renvoie le générateur concerné par un chemin modifié, ou une chaîne
+// vide pour les fichiers à ignorer (cachés, temporaires d'éditeur, dossier
+// d'import en cours).
+func (w *generatorWatcher) keyFor(path string) string {
+	for dir := range w.linked {
+		if rel, ok := relativeTo(dir, path); ok {
+			if rel == "." || !ignoredPath(rel) {
+				return dir
+			}
+			return ""
+		}
+	}
+
+	rel, ok := relativeTo(w.generatorsDir, path)
+	if !ok || rel == "." || ignoredPath(rel) {
+		return ""
+	}
+	top, _, nested := strings.Cut(rel, string(filepath.Separator))
+	key := filepath.Join(w.generatorsDir, top)
+	if nested || filepath.Ext(top) == ".js" {
+		return key
+	}
+	// Dossier de bundle créé ou supprimé
+	if _, known := w.known[key]; known {
+		return key
+	}
+	if stat, err := os.Stat(key); err == nil && stat.IsDir() {
+		return key
+	}
+	return ""
+}
+
+func relativeTo(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+func ignoredPath(rel string) bool {
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if strings.HasPrefix(part, ".") || strings.HasSuffix(part, "~") || part == "node_modules" {
+			return true
+		}
+	}
+	return false
+}
+
+// addTree surveille un dossier et ses sous-dossiers.
+func (w *generatorWatcher) addTree(root string) {
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+		if err := w.fs.Add(path); err != nil {
+			wailsruntime.LogWarningf(w.app.ctx, "surveillance de %s impossible: %v", path, err)
+		}
+		return nil
+	})
+}
+
+func (w *generatorWatcher) addLinked(dir string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.linked[dir] = true
+	w.addTree(dir)
+	w.refresh(dir)
+}
+
+func (w *generatorWatcher) removeLinked(dir string) {
+	w.mu.Lock()
+	delete(w.linked, dir)
+	for _, path := range w.fs.WatchList() {
+		if _, ok := relativeTo(dir, path); ok {
+			w.fs.Remove(path)
+		}
+	}
+	filename := w.known[dir]
+	delete(w.known, dir)
+	w.mu.Unlock()
+
+	if filename != "" {
+		w.emit("generator-removed", GeneratorEvent{Filename: filename})
+	}
+}
+
+// refresh relit un générateur et renvoie son nom précédent.
+func (w *generatorWatcher) refresh(key string) (previous string, info *GeneratorInfo) {
+	previous = w.known[key]
+	if generator, ok := w.describe(key, previous); ok {
+		w.known[key] = generator.Filename
+		return previous, &generator
+	}
+	delete(w.known, key)
+	return previous, nil
+}
+
+func (w *generatorWatcher) describe(key, previous string) (GeneratorInfo, bool) {
+	stat, err := os.Stat(key)
+	if err != nil {
+		return GeneratorInfo{}, false
+	}
+
+	if w.linked[key] {
+		// Un manifest.json en cours d'édition ne fait pas disparaître le générateur
+		prefix, _, _ := strings.Cut(previous, "/")
+		if m, _ := readBundleManifest(key); m != nil && m.PackageID != "" {
+			prefix = m.PackageID
+		}
+		if prefix == "" {
+			return GeneratorInfo{}, false
+		}
+		return readBundleInfo(key, prefix, true), true
+	}
+
+	name := filepath.Base(key)
+	if !stat.IsDir() {
+		return w.app.readGeneratorInfo(name), true
+	}
+	if _, err := os.Stat(filepath.Join(key, bundleManifestName)); err != nil {
+		return GeneratorInfo{}, false
+	}
+	return readBundleInfo(key, name, false), true
+}
+
+func (w *generatorWatcher) flush(key string) {
+	w.mu.Lock()
+	delete(w.pending, key)
+	previous, info := w.refresh(key)
+	w.mu.Unlock()
+
+	if previous != "" && (info == nil || info.Filename != previous) {
+		w.emit("generator-removed", GeneratorEvent{Filename: previous})
+	}
+	if info != nil {
+		w.emit("generator-changed", GeneratorEvent{Filename: info.Filename, Generator: info})
+	}
+}
+
+func (w *generatorWatcher) emit(name string, event GeneratorEvent) {
+	wailsruntime.EventsEmit(w.app.ctx, name, event)
+}
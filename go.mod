@@ -3,6 +3,8 @@ module VibeCraft
 go 1.24.0
 
 require (
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/ulikunitz/xz v0.5.17
 	github.com/wailsapp/wails/v2 v2.11.0
 	golang.org/x/mod v0.31.0
 )
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	defaultTempMaxAgeHours = 24
+	defaultTempMaxSizeMB   = 2048
+
+	// minFreeDiskSpace est la marge gardée libre en plus de l'estimation de
+	// chaque écriture, pour ne jamais remplir complètement le disque.
+	minFreeDiskSpace = 200 << 20
+)
+
+var ErrInsufficientDiskSpace = errors.New("espace disque insuffisant")
+
+type tempEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// cleanTempDir supprime les fichiers plus anciens que maxAge, puis les plus
+// anciens restants tant que le dossier dépasse maxSize. Une limite à 0 est
+// ignorée. Renvoie le nombre de fichiers supprimés et l'espace libéré.
+func cleanTempDir(dir string, maxAge time.Duration, maxSize int64, now time.Time) (int, int64, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("erreur lecture %s: %w", dir, err)
+	}
+
+	var entries []tempEntry
+	var total int64
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, tempEntry{
+			path:    filepath.Join(dir, dirEntry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	removed := 0
+	var freed int64
+	for _, entry := range entries {
+		expired := maxAge > 0 && now.Sub(entry.modTime) > maxAge
+		overQuota := maxSize > 0 && total > maxSize
+		if !expired && !overQuota {
+			// Les entrées sont triées : les suivantes sont plus récentes
+			break
+		}
+		if err := os.Remove(entry.path); err != nil {
+			continue
+		}
+		removed++
+		freed += entry.size
+		total -= entry.size
+	}
+
+	return removed, freed, nil
+}
+
+// sweepTempDir applique au démarrage les limites d'âge et de taille du
+// dossier temporaire, pour effacer les restes des conversions interrompues.
+func (a *App) sweepTempDir() {
+	settings := a.GetSettings()
+	cleanTempDir(getTempDir(),
+		time.Duration(settings.TempMaxAgeHours)*time.Hour,
+		int64(settings.TempMaxSizeMB)<<20,
+		time.Now())
+}
+
+// ensureDiskSpace vérifie qu'il reste au moins needed octets (plus une marge)
+// sur le disque de dir. Si l'espace libre ne peut pas être mesuré, l'écriture
+// est autorisée.
+func ensureDiskSpace(dir string, needed int64) error {
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return nil
+	}
+
+	required := uint64(max(needed, 0)) + minFreeDiskSpace
+	if free < required {
+		return fmt.Errorf("%w dans %s: %s libres, %s nécessaires",
+			ErrInsufficientDiskSpace, dir, formatBytes(free), formatBytes(required))
+	}
+	return nil
+}
+
+func formatBytes(n uint64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f Go", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f Mo", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%.1f Ko", float64(n)/(1<<10))
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCleanTempDir(t *testing.T) {
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	files := []struct {
+		name string
+		size int
+		age  time.Duration
+	}{
+		{"ancien.webm", 10, 48 * time.Hour},
+		{"hier.mp4", 30, 20 * time.Hour},
+		{"recent.mp4", 40, time.Hour},
+		{"courant.webm", 50, time.Minute},
+	}
+
+	tests := []struct {
+		name    string
+		maxAge  time.Duration
+		maxSize int64
+		kept    []string
+		freed   int64
+	}{
+		{"sans limite", 0, 0, []string{"ancien.webm", "courant.webm", "hier.mp4", "recent.mp4"}, 0},
+		{"âge", 24 * time.Hour, 0, []string{"courant.webm", "hier.mp4", "recent.mp4"}, 10},
+		{"taille", 0, 100, []string{"courant.webm", "recent.mp4"}, 40},
+		{"âge et taille", 24 * time.Hour, 60, []string{"courant.webm"}, 80},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for _, f := range files {
+			path := filepath.Join(dir, f.name)
+			if err := os.WriteFile(path, make([]byte, f.size), 0644); err != nil {
+				t.Fatal(err)
+			}
+			modTime := now.Add(-f.age)
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+		}
+		os.Mkdir(filepath.Join(dir, "sous-dossier"), 0755)
+
+		removed, freed, err := cleanTempDir(dir, tt.maxAge, tt.maxSize, now)
+		if err != nil {
+			t.Errorf("%s : %v", tt.name, err)
+			continue
+		}
+
+		entries, _ := os.ReadDir(dir)
+		var kept []string
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				kept = append(kept, entry.Name())
+			}
+		}
+		sort.Strings(kept)
+		if !reflect.DeepEqual(kept, tt.kept) || freed != tt.freed || removed != len(files)-len(tt.kept) {
+			t.Errorf("%s : %d supprimés, %d octets libérés, restent %v ; attendu %d octets, %v",
+				tt.name, removed, freed, kept, tt.freed, tt.kept)
+		}
+	}
+}
+
+func TestCleanTempDirMissing(t *testing.T) {
+	if removed, _, err := cleanTempDir(filepath.Join(t.TempDir(), "absent"), time.Hour, 0, time.Now()); err != nil || removed != 0 {
+		t.Errorf("cleanTempDir sur un dossier absent = %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	jobFFmpegDownload = "ffmpeg-download"
+	jobUpdateDownload = "update-download"
+)
+
+type jobRegistry struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{cancels: make(map[string]context.CancelFunc)}
+}
+
+// start enregistre un job annulable. La fonction retournée doit être appelée
+// à la fin du job pour libérer son identifiant.
+func (r *jobRegistry) start(parent context.Context, id string) (context.Context, func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.cancels[id]; exists {
+		return nil, nil, fmt.Errorf("tâche déjà en cours: %s", id)
+	}
+
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	r.cancels[id] = cancel
+
+	return ctx, func() {
+		r.mu.Lock()
+		delete(r.cancels, id)
+		r.mu.Unlock()
+		cancel()
+	}, nil
+}
+
+func (r *jobRegistry) cancel(id string) bool {
+	r.mu.Lock()
+	cancel, exists := r.cancels[id]
+	r.mu.Unlock()
+
+	if exists {
+		cancel()
+	}
+	return exists
+}
+
+// CancelJob interrompt une tâche en cours. Les conversions sont identifiées
+// par leur fichier de sortie, les téléchargements par "ffmpeg-download" et
+// "update-download".
+func (a *App) CancelJob(id string) error {
+	if !a.jobs.cancel(id) {
+		return fmt.Errorf("aucune tâche en cours: %s", id)
+	}
+	return nil
+}
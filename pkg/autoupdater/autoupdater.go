@@ -2,6 +2,7 @@ package autoupdater
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -61,10 +62,15 @@ func NewUpdater(currentVersion, githubRepo string) *Updater {
 	}
 }
 
-func (u *Updater) CheckForUpdates() (*UpdateInfo, error) {
+func (u *Updater) CheckForUpdates(ctx context.Context) (*UpdateInfo, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", u.githubRepo)
 
-	resp, err := u.httpClient.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la création de la requête: %w", err)
+	}
+
+	resp, err := u.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la vérification des mises à jour: %w", err)
 	}
@@ -136,9 +142,17 @@ func (u *Updater) getPlatformSuffix() string {
 	}
 }
 
-func (u *Updater) DownloadUpdate(downloadURL string, progressCallback func(UpdateProgress)) (string, error) {
-	resp, err := u.httpClient.Get(downloadURL)
+func (u *Updater) DownloadUpdate(ctx context.Context, downloadURL string, progressCallback func(UpdateProgress)) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("erreur lors de la création de la requête: %w", err)
+	}
+
+	resp, err := u.httpClient.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("téléchargement annulé: %w", ctx.Err())
+		}
 		return "", fmt.Errorf("erreur lors du téléchargement: %w", err)
 	}
 	defer resp.Body.Close()
@@ -183,6 +197,11 @@ func (u *Updater) DownloadUpdate(downloadURL string, progressCallback func(Updat
 			break
 		}
 		if err != nil {
+			out.Close()
+			os.Remove(tmpFile)
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("téléchargement annulé: %w", ctx.Err())
+			}
 			return "", fmt.Errorf("erreur lors du téléchargement: %w", err)
 		}
 	}
@@ -0,0 +1,77 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	AlphaFormatWebM   = "webm"
+	AlphaFormatProRes = "prores"
+)
+
+// alphaEncoders décrit, pour chaque format transparent, l'encodeur et les
+// arguments qui conservent le canal alpha de bout en bout.
+var alphaEncoders = map[string]struct {
+	encoder string
+	args    []string
+}{
+	AlphaFormatWebM: {
+		encoder: "libvpx-vp9",
+		args: []string{
+			"-pix_fmt", "yuva420p",
+			"-crf", "30",
+			"-b:v", "0",
+			"-auto-alt-ref", "0",
+		},
+	},
+	AlphaFormatProRes: {
+		encoder: "prores_ks",
+		args: []string{
+			"-profile:v", "4444",
+			"-pix_fmt", "yuva444p10le",
+			"-alpha_bits", "16",
+			"-vendor", "apl0",
+		},
+	},
+}
+
+// alphaDecoder force le décodeur libvpx pour les sources VP8/VP9 : les
+// décodeurs natifs de ffmpeg ignorent la couche alpha des WebM.
+func (f *FFmpeg) alphaDecoder(ctx context.Context, inputPath string) string {
+	info, err := f.Probe(ctx, inputPath)
+	if err != nil {
+		return ""
+	}
+	switch info.Codec {
+	case "vp8":
+		return "libvpx"
+	case "vp9":
+		return "libvpx-vp9"
+	}
+	return ""
+}
+
+// ConvertWithAlpha exporte une vidéo en conservant la transparence : WebM VP9
+// (yuva420p) pour le web, ou MOV ProRes 4444 pour le montage.
+func (f *FFmpeg) ConvertWithAlpha(ctx context.Context, inputPath, outputPath, format string, duration float64, progressCallback func(ConvertProgress)) error {
+	alpha, ok := alphaEncoders[format]
+	if !ok {
+		return fmt.Errorf("format transparent inconnu: %s", format)
+	}
+
+	encoder, err := f.resolveEncoder(ctx, alpha.encoder)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	if decoder := f.alphaDecoder(ctx, inputPath); decoder != "" {
+		args = append(args, "-c:v", decoder)
+	}
+	args = append(args, "-i", inputPath, "-c:v", encoder)
+	args = append(args, alpha.args...)
+	args = append(args, "-an", "-y", outputPath)
+
+	return f.convert(ctx, inputPath, outputPath, args, duration, progressCallback)
+}
@@ -0,0 +1,121 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type WebPOptions struct {
+	Lossless bool `json:"lossless"`
+	Quality  int  `json:"quality"`
+	Loop     int  `json:"loop"`
+	Width    int  `json:"width"`
+	FPS      int  `json:"fps"`
+}
+
+type APNGOptions struct {
+	Loop  int `json:"loop"`
+	Width int `json:"width"`
+	FPS   int `json:"fps"`
+}
+
+func DefaultWebPOptions() WebPOptions {
+	return WebPOptions{
+		Quality: 80,
+		Width:   720,
+		FPS:     30,
+	}
+}
+
+func DefaultAPNGOptions() APNGOptions {
+	return APNGOptions{
+		Width: 480,
+		FPS:   20,
+	}
+}
+
+func (o WebPOptions) Validate() error {
+	if o.Quality < 0 || o.Quality > 100 {
+		return fmt.Errorf("qualité WebP invalide: %d", o.Quality)
+	}
+	if o.Width < 0 || o.FPS < 0 || o.Loop < 0 {
+		return fmt.Errorf("options WebP invalides")
+	}
+	return nil
+}
+
+func (o APNGOptions) Validate() error {
+	if o.Width < 0 || o.FPS < 0 || o.Loop < 0 {
+		return fmt.Errorf("options APNG invalides")
+	}
+	return nil
+}
+
+// scaleFilter construit la chaîne de filtres fps/scale commune aux exports
+// d'images animées. Une valeur nulle conserve celle de la source.
+func scaleFilter(fps, width int) string {
+	var filters []string
+	if fps > 0 {
+		filters = append(filters, "fps="+strconv.Itoa(fps))
+	}
+	if width > 0 {
+		filters = append(filters, fmt.Sprintf("scale=%d:-1:flags=lanczos", width))
+	}
+	if len(filters) == 0 {
+		return "null"
+	}
+	return strings.Join(filters, ",")
+}
+
+func (f *FFmpeg) ConvertToWebP(ctx context.Context, inputPath, webpPath string, opts WebPOptions, duration float64, progressCallback func(ConvertProgress)) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	encoder := "libwebp_anim"
+	if !f.HasEncoder(ctx, encoder) {
+		encoder = "libwebp"
+	}
+
+	lossless := "0"
+	if opts.Lossless {
+		lossless = "1"
+	}
+
+	args := []string{
+		"-i", inputPath,
+		"-vf", scaleFilter(opts.FPS, opts.Width),
+		"-c:v", encoder,
+		"-lossless", lossless,
+		"-quality", strconv.Itoa(opts.Quality),
+		"-loop", strconv.Itoa(opts.Loop),
+		"-an",
+		"-y",
+		webpPath,
+	}
+
+	return f.convert(ctx, inputPath, webpPath, args, duration, progressCallback)
+}
+
+// ConvertToAPNG exporte un PNG animé. Loop correspond à -plays : 0 boucle
+// à l'infini, n joue l'animation n fois.
+func (f *FFmpeg) ConvertToAPNG(ctx context.Context, inputPath, apngPath string, opts APNGOptions, duration float64, progressCallback func(ConvertProgress)) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	args := []string{
+		"-i", inputPath,
+		"-vf", scaleFilter(opts.FPS, opts.Width),
+		"-c:v", "apng",
+		"-plays", strconv.Itoa(opts.Loop),
+		"-f", "apng",
+		"-an",
+		"-y",
+		apngPath,
+	}
+
+	return f.convert(ctx, inputPath, apngPath, args, duration, progressCallback)
+}
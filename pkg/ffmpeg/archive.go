@@ -0,0 +1,163 @@
+package ffmpeg
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+func archiveExt(name string) string {
+	for _, ext := range []string{".tar.xz", ".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// installedBinaries liste les exécutables extraits de l'archive BtbN.
+func installedBinaries() []string {
+	return []string{executableName("ffmpeg"), executableName("ffprobe")}
+}
+
+func (f *FFmpeg) extractFFmpeg(archivePath, destDir string) error {
+	binaryNames := installedBinaries()
+
+	var err error
+	var extracted map[string]bool
+	switch archiveExt(archivePath) {
+	case ".zip":
+		extracted, err = extractFromZip(archivePath, destDir, binaryNames)
+	case ".tar.xz", ".tar.gz", ".tgz":
+		extracted, err = extractFromTar(archivePath, destDir, binaryNames)
+	default:
+		return fmt.Errorf("format d'archive non supporté: %s", filepath.Base(archivePath))
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, name := range binaryNames {
+		if !extracted[name] {
+			return fmt.Errorf("binaire %s non trouvé dans l'archive", name)
+		}
+	}
+	return nil
+}
+
+func matchBinaryEntry(name string, binaryNames []string) string {
+	for _, binaryName := range binaryNames {
+		if strings.HasSuffix(name, "/bin/"+binaryName) || strings.HasSuffix(name, "\\bin\\"+binaryName) {
+			return binaryName
+		}
+	}
+	return ""
+}
+
+func extractFromZip(zipPath, destDir string, binaryNames []string) (map[string]bool, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	extracted := make(map[string]bool)
+	for _, file := range reader.File {
+		binaryName := matchBinaryEntry(file.Name, binaryNames)
+		if binaryName == "" {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		err = writeBinary(rc, filepath.Join(destDir, binaryName))
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		extracted[binaryName] = true
+	}
+
+	return extracted, nil
+}
+
+func extractFromTar(tarPath, destDir string, binaryNames []string) (map[string]bool, error) {
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var stream io.Reader
+	if archiveExt(tarPath) == ".tar.xz" {
+		stream, err = xz.NewReader(file)
+	} else {
+		var gz *gzip.Reader
+		gz, err = gzip.NewReader(file)
+		if err == nil {
+			defer gz.Close()
+		}
+		stream = gz
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := make(map[string]bool)
+	reader := tar.NewReader(stream)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if binaryName := matchBinaryEntry(header.Name, binaryNames); binaryName != "" {
+			if err := writeBinary(reader, filepath.Join(destDir, binaryName)); err != nil {
+				return nil, err
+			}
+			extracted[binaryName] = true
+		}
+	}
+
+	return extracted, nil
+}
+
+func writeBinary(r io.Reader, destPath string) error {
+	destFile, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(destFile, r)
+	destFile.Close()
+
+	if err != nil {
+		os.Remove(destPath)
+		return err
+	}
+
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(destPath, 0755); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type AudioTrack struct {
+	Path string `json:"path"`
+	// StartOffset décale le début de la musique dans la vidéo, en secondes.
+	StartOffset float64 `json:"startOffset"`
+	// TrimStart et TrimEnd délimitent l'extrait utilisé dans le fichier audio.
+	// TrimEnd à 0 garde le fichier jusqu'à la fin.
+	TrimStart float64 `json:"trimStart"`
+	TrimEnd   float64 `json:"trimEnd"`
+	FadeIn    float64 `json:"fadeIn"`
+	FadeOut   float64 `json:"fadeOut"`
+	// Volume est un facteur linéaire : 1 conserve le niveau d'origine.
+	Volume float64 `json:"volume"`
+	Loop   bool    `json:"loop"`
+}
+
+func (t AudioTrack) Validate() error {
+	if t.Path == "" {
+		return fmt.Errorf("aucun fichier audio sélectionné")
+	}
+	if _, err := os.Stat(t.Path); err != nil {
+		return fmt.Errorf("fichier audio inaccessible: %w", err)
+	}
+	if t.StartOffset < 0 || t.TrimStart < 0 || t.FadeIn < 0 || t.FadeOut < 0 || t.Volume < 0 {
+		return fmt.Errorf("paramètres audio invalides")
+	}
+	if t.TrimEnd != 0 && t.TrimEnd <= t.TrimStart {
+		return fmt.Errorf("fin de l'extrait audio (%.2fs) avant son début (%.2fs)", t.TrimEnd, t.TrimStart)
+	}
+	return nil
+}
+
+// videoDuration renvoie la durée fournie par l'appelant ou, à défaut, celle
+// lue par ffprobe, plus fiable, puis celle de l'en-tête lu par ffmpeg.
+func (f *FFmpeg) videoDuration(ctx context.Context, path string, duration float64) float64 {
+	if duration > 0 {
+		return duration
+	}
+	if info, err := f.Probe(ctx, path); err == nil && info.Duration > 0 {
+		return info.Duration
+	}
+	return f.mediaDuration(ctx, path)
+}
+
+// audioFilter construit la chaîne appliquée à la piste : extrait, boucle,
+// volume, fondus puis décalage. Elle est complétée par un silence pour que
+// la vidéo fixe toujours la durée finale.
+func (t AudioTrack) audioFilter(input string, videoDuration float64) string {
+	var filters []string
+
+	trim := "atrim=start=" + formatSeconds(t.TrimStart)
+	if t.TrimEnd > 0 {
+		trim += ":end=" + formatSeconds(t.TrimEnd)
+	}
+	filters = append(filters, trim, "asetpts=PTS-STARTPTS")
+
+	if t.Loop {
+		filters = append(filters, "aloop=loop=-1:size=2147483647")
+	}
+
+	// Longueur audible de la piste une fois placée dans la vidéo
+	audible := videoDuration - t.StartOffset
+	if audible > 0 {
+		filters = append(filters, "atrim=duration="+formatSeconds(audible))
+	}
+
+	volume := t.Volume
+	if volume == 0 {
+		volume = 1
+	}
+	filters = append(filters, "volume="+formatSeconds(volume))
+
+	if t.FadeIn > 0 {
+		filters = append(filters, "afade=t=in:st=0:d="+formatSeconds(t.FadeIn))
+	}
+	if t.FadeOut > 0 && audible > 0 {
+		fadeStart := max(audible-t.FadeOut, 0)
+		filters = append(filters, fmt.Sprintf("afade=t=out:st=%s:d=%s", formatSeconds(fadeStart), formatSeconds(t.FadeOut)))
+	}
+
+	if t.StartOffset > 0 {
+		filters = append(filters, fmt.Sprintf("adelay=%d:all=1", int(t.StartOffset*1000)))
+	}
+
+	filters = append(filters, "apad")
+
+	return fmt.Sprintf("[%s]%s[aout]", input, strings.Join(filters, ","))
+}
+
+func formatSeconds(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+// audioCodecFor choisit un codec audio compatible avec le conteneur de sortie.
+func audioCodecFor(outputPath string) string {
+	if strings.EqualFold(filepath.Ext(outputPath), ".webm") {
+		return "libopus"
+	}
+	return "aac"
+}
+
+func containerArgs(outputPath string) []string {
+	if ext := strings.ToLower(filepath.Ext(outputPath)); ext == ".mp4" || ext == ".mov" {
+		return []string{"-movflags", "+faststart"}
+	}
+	return nil
+}
+
+// MuxAudio ajoute une piste musicale à une vidéo existante. La vidéo est
+// copiée sans réencodage ; la musique est bouclée ou coupée pour durer
+// exactement aussi longtemps qu'elle. duration est la durée de la vidéo si
+// l'appelant la connaît (0 sinon) : les WebM de MediaRecorder ne l'annoncent
+// pas.
+func (f *FFmpeg) MuxAudio(ctx context.Context, videoPath, outputPath string, track AudioTrack, duration float64, progressCallback func(ConvertProgress)) error {
+	if err := track.Validate(); err != nil {
+		return err
+	}
+	if err := f.checkSource(videoPath); err != nil {
+		return err
+	}
+
+	duration = f.videoDuration(ctx, videoPath, duration)
+	if duration == 0 && track.FadeOut > 0 {
+		return fmt.Errorf("durée de la vidéo inconnue, impossible d'appliquer le fondu de sortie")
+	}
+
+	args := []string{
+		"-i", videoPath,
+		"-i", track.Path,
+		"-filter_complex", track.audioFilter("1:a", duration),
+		"-map", "0:v",
+		"-map", "[aout]",
+		"-c:v", "copy",
+		"-c:a", audioCodecFor(outputPath),
+		"-shortest",
+	}
+	if duration > 0 {
+		args = append(args, "-t", formatSeconds(duration))
+	}
+	args = append(args, containerArgs(outputPath)...)
+	args = append(args, "-y", outputPath)
+
+	if err := f.runWithProgress(ctx, args, duration, progressCallback); err != nil {
+		os.Remove(outputPath)
+		return err
+	}
+
+	return checkOutput(outputPath)
+}
@@ -0,0 +1,62 @@
+package ffmpeg
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAudioFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		track    AudioTrack
+		duration float64
+		want     string
+	}{
+		{
+			name:     "piste simple",
+			track:    AudioTrack{},
+			duration: 5,
+			want:     "[1:a]atrim=start=0.000,asetpts=PTS-STARTPTS,atrim=duration=5.000,volume=1.000,apad[aout]",
+		},
+		{
+			name:     "extrait en boucle avec fondus",
+			track:    AudioTrack{TrimStart: 2, TrimEnd: 4, Loop: true, Volume: 0.5, FadeIn: 1, FadeOut: 1},
+			duration: 10,
+			want: "[1:a]atrim=start=2.000:end=4.000,asetpts=PTS-STARTPTS,aloop=loop=-1:size=2147483647," +
+				"atrim=duration=10.000,volume=0.500,afade=t=in:st=0:d=1.000,afade=t=out:st=9.000:d=1.000,apad[aout]",
+		},
+		{
+			// Le fondu de sortie se cale sur la fin de la vidéo, pas de la piste
+			name:     "décalage",
+			track:    AudioTrack{StartOffset: 1.5, FadeOut: 2},
+			duration: 5,
+			want: "[1:a]atrim=start=0.000,asetpts=PTS-STARTPTS,atrim=duration=3.500,volume=1.000," +
+				"afade=t=out:st=1.500:d=2.000,adelay=1500:all=1,apad[aout]",
+		},
+		{
+			name:     "fondu plus long que la vidéo",
+			track:    AudioTrack{FadeOut: 8},
+			duration: 5,
+			want:     "[1:a]atrim=start=0.000,asetpts=PTS-STARTPTS,atrim=duration=5.000,volume=1.000,afade=t=out:st=0.000:d=8.000,apad[aout]",
+		},
+		{
+			name:     "durée inconnue",
+			track:    AudioTrack{FadeOut: 1},
+			duration: 0,
+			want:     "[1:a]atrim=start=0.000,asetpts=PTS-STARTPTS,volume=1.000,apad[aout]",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.track.audioFilter("1:a", tt.duration); got != tt.want {
+			t.Errorf("%s :\n  obtenu  %s\n  attendu %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVideoDurationPrefersCaller(t *testing.T) {
+	f := NewFFmpeg()
+	if got := f.videoDuration(context.Background(), "absent.webm", 5); got != 5 {
+		t.Errorf("videoDuration = %v ; attendu 5", got)
+	}
+}
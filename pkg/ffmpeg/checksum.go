@@ -0,0 +1,136 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	checksumsFile = "checksums.sha256"
+	// installMarkerFile distingue une installation de cette version, dont le
+	// fichier de sommes a disparu, d'une installation antérieure qui n'en
+	// avait pas
+	installMarkerFile = ".installed"
+)
+
+var ErrChecksumMismatch = errors.New("somme de contrôle SHA-256 invalide")
+
+// errUnverifiedInstall signale une copie installée avant l'enregistrement
+// des sommes de contrôle : elle reste utilisable mais ne peut être vérifiée.
+var errUnverifiedInstall = errors.New("installation antérieure aux sommes de contrôle")
+
+// fetchChecksums télécharge le fichier de sommes publié avec la release BtbN
+// ("<sha256>  <fichier>" par ligne).
+func fetchChecksums(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadBaseURL+"/"+checksumsFile, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erreur création requête: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erreur téléchargement des sommes de contrôle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erreur HTTP sommes de contrôle: %d", resp.StatusCode)
+	}
+
+	return parseChecksums(resp.Body)
+}
+
+func parseChecksums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+
+	return sums, scanner.Err()
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func checkSum(name, expected, actual string) error {
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("%w pour %s: attendu %s, obtenu %s", ErrChecksumMismatch, name, expected, actual)
+	}
+	return nil
+}
+
+// writeInstalledChecksums enregistre le hash des binaires extraits pour que
+// IsInstalled puisse détecter une corruption ou une modification ultérieure,
+// ainsi que le marqueur d'installation.
+func writeInstalledChecksums(dir string, names []string) error {
+	var sb strings.Builder
+	for _, name := range names {
+		sum, err := hashFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&sb, "%s  %s\n", sum, name)
+	}
+	if err := os.WriteFile(filepath.Join(dir, installMarkerFile), nil, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, checksumsFile), []byte(sb.String()), 0644)
+}
+
+// verifyInstalled compare un binaire installé au hash enregistré lors du
+// téléchargement. Sans fichier de sommes, la copie est refusée si elle a été
+// installée par cette version, et signalée par errUnverifiedInstall sinon.
+func verifyInstalled(binaryPath string) error {
+	dir := filepath.Dir(binaryPath)
+	file, err := os.Open(filepath.Join(dir, checksumsFile))
+	if err != nil {
+		if _, markerErr := os.Stat(filepath.Join(dir, installMarkerFile)); markerErr == nil {
+			return fmt.Errorf("%s introuvable: %w", checksumsFile, err)
+		}
+		return errUnverifiedInstall
+	}
+	defer file.Close()
+
+	sums, err := parseChecksums(file)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(binaryPath)
+	expected, ok := sums[name]
+	if !ok {
+		return fmt.Errorf("somme de contrôle de %s absente de %s", name, checksumsFile)
+	}
+
+	actual, err := hashFile(binaryPath)
+	if err != nil {
+		return err
+	}
+	return checkSum(name, expected, actual)
+}
@@ -0,0 +1,150 @@
+package ffmpeg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name: "format sha256sum",
+			input: "ABCDEF01  ffmpeg-master-latest-win64-gpl.zip\n" +
+				"12345678 *ffmpeg-master-latest-linux64-gpl.tar.xz\n",
+			want: map[string]string{
+				"ffmpeg-master-latest-win64-gpl.zip":      "abcdef01",
+				"ffmpeg-master-latest-linux64-gpl.tar.xz": "12345678",
+			},
+		},
+		{
+			name:  "lignes invalides ignorées",
+			input: "\n# commentaire ici\nabcdef01\nabcdef01  ffmpeg\n",
+			want:  map[string]string{"ffmpeg": "abcdef01"},
+		},
+		{
+			name:  "vide",
+			input: "",
+			want:  map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := parseChecksums(strings.NewReader(tt.input))
+		if err != nil {
+			t.Errorf("%s : %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s : %v ; attendu %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSum(t *testing.T) {
+	if err := checkSum("ffmpeg", "ABCDEF", "abcdef"); err != nil {
+		t.Errorf("casse différente refusée : %v", err)
+	}
+	if err := checkSum("ffmpeg", "abcdef", "012345"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("checkSum = %v ; attendu %v", err, ErrChecksumMismatch)
+	}
+}
+
+func TestVerifyInstalled(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(dir, binary string)
+		want    func(error) bool
+	}{
+		{
+			name:    "installation intacte",
+			prepare: func(dir, binary string) {},
+			want:    func(err error) bool { return err == nil },
+		},
+		{
+			name: "binaire modifié",
+			prepare: func(dir, binary string) {
+				os.WriteFile(binary, []byte("modifié"), 0755)
+			},
+			want: func(err error) bool { return errors.Is(err, ErrChecksumMismatch) },
+		},
+		{
+			name: "sommes supprimées",
+			prepare: func(dir, binary string) {
+				os.Remove(filepath.Join(dir, checksumsFile))
+			},
+			want: func(err error) bool { return err != nil && !errors.Is(err, errUnverifiedInstall) },
+		},
+		{
+			name: "binaire absent des sommes",
+			prepare: func(dir, binary string) {
+				os.WriteFile(filepath.Join(dir, checksumsFile), []byte("abcdef  ffprobe\n"), 0644)
+			},
+			want: func(err error) bool { return err != nil && !errors.Is(err, errUnverifiedInstall) },
+		},
+		{
+			name: "installation antérieure aux sommes",
+			prepare: func(dir, binary string) {
+				os.Remove(filepath.Join(dir, checksumsFile))
+				os.Remove(filepath.Join(dir, installMarkerFile))
+			},
+			want: func(err error) bool { return errors.Is(err, errUnverifiedInstall) },
+		},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		binary := filepath.Join(dir, "ffmpeg")
+		if err := os.WriteFile(binary, []byte("binaire"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeInstalledChecksums(dir, []string{"ffmpeg"}); err != nil {
+			t.Fatal(err)
+		}
+
+		tt.prepare(dir, binary)
+		if err := verifyInstalled(binary); !tt.want(err) {
+			t.Errorf("%s : verifyInstalled = %v", tt.name, err)
+		}
+	}
+}
+
+func TestResolveBinaryManagedWarnings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("PATH", "")
+
+	f := NewFFmpeg()
+	dir := filepath.Dir(f.getFFmpegPath())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(f.getFFmpegPath(), []byte("binaire"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Installation antérieure : utilisée, avec un avertissement
+	if f.binary() == "" || f.managedErr == nil {
+		t.Errorf("copie non vérifiée : binaire %q, avertissement %v", f.binaryPath, f.managedErr)
+	}
+
+	// Installation de cette version dont les sommes ont disparu : écartée
+	if err := os.WriteFile(filepath.Join(dir, installMarkerFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	f.SetCustomPath("")
+	if f.binary() != "" || f.managedErr == nil {
+		t.Errorf("sommes supprimées : binaire %q, erreur %v", f.binaryPath, f.managedErr)
+	}
+	if _, err := f.GetInfo(t.Context()); err == nil || !strings.Contains(err.Error(), checksumsFile) {
+		t.Errorf("GetInfo = %v ; attendu l'erreur de vérification", err)
+	}
+}
@@ -0,0 +1,129 @@
+package ffmpeg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Les estimations de taille servent à refuser une conversion qui remplirait
+// le disque. Les débits retenus (en bits par pixel et par image) visent le
+// haut de la fourchette observée sur des animations.
+const (
+	bppProRes       = 6.0 // ProRes 4444, 10 bits avec alpha
+	bppVP9Alpha     = 0.5
+	bppGIF          = 1.0
+	bppWebPLossy    = 0.5
+	bppWebPLossless = 3.0
+	bppAPNG         = 6.0
+
+	// bppCRFReference est le débit d'un H.264 à CRF 20, qui double tous les
+	// 6 points de CRF en moins
+	bppCRFReference = 0.15
+	crfReference    = 20
+
+	// audioBitrate couvre largement les pistes AAC et Opus produites
+	audioBitrate = 320_000
+
+	// maxSourceBitrate est le débit maximal demandé à MediaRecorder. Les
+	// WebM qu'il produit n'annoncent pas leur durée : elle est déduite de la
+	// taille du fichier à ce débit, ce qui la minore.
+	maxSourceBitrate = 20_000_000
+)
+
+// duration renvoie la durée de la source, ou une estimation basse à partir
+// de sa taille si elle n'est pas annoncée.
+func (m MediaInfo) duration() float64 {
+	if m.Duration > 0 {
+		return m.Duration
+	}
+	bitrate := float64(m.Bitrate)
+	if bitrate <= 0 {
+		bitrate = maxSourceBitrate
+	}
+	return float64(m.Size) * 8 / bitrate
+}
+
+// estimateSize calcule la taille d'une sortie de débit bpp, redimensionnée à
+// width de large (hauteur proportionnelle) et à fps images par seconde. Une
+// valeur nulle conserve celle de la source.
+func (m MediaInfo) estimateSize(width, fps int, bpp float64) int64 {
+	pixels := float64(m.Width * m.Height)
+	if width > 0 && m.Width > 0 {
+		pixels *= math.Pow(float64(width)/float64(m.Width), 2)
+	}
+	rate := m.FrameRate
+	if fps > 0 {
+		rate = float64(fps)
+	}
+	return int64(pixels * rate * m.duration() * bpp / 8)
+}
+
+func (m MediaInfo) audioSize() int64 {
+	return int64(m.duration() * audioBitrate / 8)
+}
+
+// EstimateSize estime la taille d'une vidéo encodée avec ces options, piste
+// audio comprise.
+func (o EncodeOptions) EstimateSize(m MediaInfo) int64 {
+	if bitrate := parseBitrate(o.Bitrate); bitrate > 0 {
+		return int64(m.duration()*bitrate/8) + m.audioSize()
+	}
+	crf := o.CRF
+	if crf == 0 {
+		crf = crfReference
+	}
+	bpp := bppCRFReference * math.Pow(2, float64(crfReference-crf)/6)
+	return m.estimateSize(0, 0, bpp) + m.audioSize()
+}
+
+func (o GIFOptions) EstimateSize(m MediaInfo) int64 {
+	return m.estimateSize(o.Width, o.FPS, bppGIF)
+}
+
+func (o WebPOptions) EstimateSize(m MediaInfo) int64 {
+	if o.Lossless {
+		return m.estimateSize(o.Width, o.FPS, bppWebPLossless)
+	}
+	return m.estimateSize(o.Width, o.FPS, bppWebPLossy)
+}
+
+func (o APNGOptions) EstimateSize(m MediaInfo) int64 {
+	return m.estimateSize(o.Width, o.FPS, bppAPNG)
+}
+
+// EstimateAlphaSize estime la taille d'un export transparent.
+func EstimateAlphaSize(format string, m MediaInfo) int64 {
+	if format == AlphaFormatProRes {
+		return m.estimateSize(0, 0, bppProRes) + m.audioSize()
+	}
+	return m.estimateSize(0, 0, bppVP9Alpha) + m.audioSize()
+}
+
+// EstimateRemuxSize estime la taille d'une vidéo copiée sans réencodage à
+// laquelle on ajoute ou remplace la piste audio.
+func EstimateRemuxSize(m MediaInfo) int64 {
+	return m.Size + m.audioSize()
+}
+
+// parseBitrate lit un débit au format ffmpeg ("800k", "5M").
+func parseBitrate(value string) float64 {
+	value = strings.TrimSpace(value)
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "k"), strings.HasSuffix(value, "K"):
+		multiplier = 1e3
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1e9
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return n * multiplier
+}
@@ -1,21 +1,33 @@
 package ffmpeg
 
 import (
-	"archive/zip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strings"
+	"sync"
 	"time"
 )
 
+// FFmpeg est partagé par les méthodes de l'application, appelées en
+// parallèle par le frontend : mu protège le binaire résolu et son cache.
 type FFmpeg struct {
-	BinaryPath string
+	mu         sync.Mutex
+	binaryPath string
+	customPath string
+	source     string
+	info       *Info
+
+	// verified est l'état de la copie téléchargée lors de la vérification de
+	// son hash, managedErr la raison pour laquelle elle a été écartée ou n'a
+	// pas pu être vérifiée
+	verified   os.FileInfo
+	managedErr error
 }
 
 func NewFFmpeg() *FFmpeg {
@@ -23,33 +35,45 @@ func NewFFmpeg() *FFmpeg {
 }
 
 func (f *FFmpeg) IsInstalled() bool {
-	if f.BinaryPath == "" {
-		f.BinaryPath = f.getFFmpegPath()
-	}
+	return f.binary() != ""
+}
 
-	if f.BinaryPath == "" {
-		return false
+// binary renvoie le chemin du ffmpeg à utiliser, résolu au premier appel,
+// ou une chaîne vide s'il est introuvable.
+func (f *FFmpeg) binary() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// La copie téléchargée est revérifiée dès qu'elle a changé sur disque
+	if f.binaryPath != "" {
+		if stat, err := os.Stat(f.binaryPath); err == nil && (f.source != SourceManaged || sameFile(stat, f.verified)) {
+			return f.binaryPath
+		}
 	}
 
-	_, err := os.Stat(f.BinaryPath)
-	return err == nil
+	f.binaryPath, f.source = f.resolveBinary()
+	return f.binaryPath
 }
 
-func (f *FFmpeg) getFFmpegPath() string {
-	homeDir, _ := os.UserHomeDir()
-	vibeDir := filepath.Join(homeDir, ".vibecraft")
+func sameFile(a, b os.FileInfo) bool {
+	return b != nil && os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
 
-	var binaryName string
+func executableName(name string) string {
 	if runtime.GOOS == "windows" {
-		binaryName = "ffmpeg.exe"
-	} else {
-		binaryName = "ffmpeg"
+		return name + ".exe"
 	}
+	return name
+}
 
-	return filepath.Join(vibeDir, "ffmpeg", binaryName)
+func (f *FFmpeg) getFFmpegPath() string {
+	homeDir, _ := os.UserHomeDir()
+	vibeDir := filepath.Join(homeDir, ".vibecraft")
+
+	return filepath.Join(vibeDir, "ffmpeg", executableName("ffmpeg"))
 }
 
-func (f *FFmpeg) Download(progressCallback func(progress float64)) error {
+func (f *FFmpeg) Download(ctx context.Context, progressCallback func(progress float64)) error {
 	homeDir, _ := os.UserHomeDir()
 	vibeDir := filepath.Join(homeDir, ".vibecraft")
 	ffmpegDir := filepath.Join(vibeDir, "ffmpeg")
@@ -58,12 +82,21 @@ func (f *FFmpeg) Download(progressCallback func(progress float64)) error {
 
 	downloadURL := f.getDownloadURL()
 	if downloadURL == "" {
-		return fmt.Errorf("système non supporté: %s/%s", runtime.GOOS, runtime.GOARCH)
+		return fmt.Errorf("système non supporté: %s/%s, installez ffmpeg sur le système ou indiquez son chemin dans les réglages", runtime.GOOS, runtime.GOARCH)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
+	checksums, err := fetchChecksums(ctx)
+	if err != nil {
+		return err
+	}
+	expectedSum, ok := checksums[f.getAssetName()]
+	if !ok {
+		return fmt.Errorf("somme de contrôle introuvable pour %s", f.getAssetName())
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
 	if err != nil {
 		return fmt.Errorf("erreur création requête: %w", err)
@@ -71,6 +104,9 @@ func (f *FFmpeg) Download(progressCallback func(progress float64)) error {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("téléchargement annulé: %w", ctx.Err())
+		}
 		return fmt.Errorf("erreur téléchargement: %w", err)
 	}
 	defer resp.Body.Close()
@@ -79,7 +115,7 @@ func (f *FFmpeg) Download(progressCallback func(progress float64)) error {
 		return fmt.Errorf("erreur HTTP: %d", resp.StatusCode)
 	}
 
-	tempFile := filepath.Join(ffmpegDir, "ffmpeg_temp.zip")
+	tempFile := filepath.Join(ffmpegDir, "ffmpeg_temp"+archiveExt(downloadURL))
 	file, err := os.Create(tempFile)
 	if err != nil {
 		return fmt.Errorf("erreur création fichier: %w", err)
@@ -93,19 +129,35 @@ func (f *FFmpeg) Download(progressCallback func(progress float64)) error {
 		Callback: progressCallback,
 	}
 
-	_, err = io.Copy(file, reader)
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hasher), reader)
 	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("téléchargement annulé: %w", ctx.Err())
+		}
 		return fmt.Errorf("erreur écriture fichier: %w", err)
 	}
 
 	file.Close()
 
+	if err := checkSum(f.getAssetName(), expectedSum, hex.EncodeToString(hasher.Sum(nil))); err != nil {
+		return err
+	}
+
 	err = f.extractFFmpeg(tempFile, ffmpegDir)
 	if err != nil {
 		return fmt.Errorf("erreur extraction: %w", err)
 	}
 
-	f.BinaryPath = f.getFFmpegPath()
+	if err := writeInstalledChecksums(ffmpegDir, installedBinaries()); err != nil {
+		return fmt.Errorf("erreur enregistrement somme de contrôle: %w", err)
+	}
+
+	f.mu.Lock()
+	f.binaryPath, f.source = "", ""
+	f.info = nil
+	f.managedErr = nil
+	f.mu.Unlock()
 
 	if !f.IsInstalled() {
 		return fmt.Errorf("échec installation ffmpeg")
@@ -114,113 +166,91 @@ func (f *FFmpeg) Download(progressCallback func(progress float64)) error {
 	return nil
 }
 
+const downloadBaseURL = "https://github.com/BtbN/FFmpeg-Builds/releases/download/latest"
+
+// platformAssets associe chaque GOOS/GOARCH à l'archive BtbN correspondante.
+// BtbN publie des .zip pour Windows et des .tar.xz pour Linux, mais aucune
+// version macOS : sur Mac, ffmpeg doit être installé sur le système (Homebrew)
+// ou désigné par son chemin.
+var platformAssets = map[string]string{
+	"windows/amd64": "ffmpeg-master-latest-win64-gpl.zip",
+	"windows/arm64": "ffmpeg-master-latest-winarm64-gpl.zip",
+	"linux/amd64":   "ffmpeg-master-latest-linux64-gpl.tar.xz",
+	"linux/arm64":   "ffmpeg-master-latest-linuxarm64-gpl.tar.xz",
+}
+
+func (f *FFmpeg) getAssetName() string {
+	return platformAssets[runtime.GOOS+"/"+runtime.GOARCH]
+}
+
 func (f *FFmpeg) getDownloadURL() string {
-	baseURL := "https://github.com/BtbN/FFmpeg-Builds/releases/download/latest"
+	assetName := f.getAssetName()
+	if assetName == "" {
+		return ""
+	}
+	return downloadBaseURL + "/" + assetName
+}
 
-	switch runtime.GOOS {
-	case "windows":
-		if runtime.GOARCH == "amd64" {
-			return baseURL + "/ffmpeg-master-latest-win64-gpl.zip"
-		}
-	case "darwin":
-		if runtime.GOARCH == "amd64" || runtime.GOARCH == "arm64" {
-			return baseURL + "/ffmpeg-master-latest-macos64-gpl.zip"
-		}
-	case "linux":
-		if runtime.GOARCH == "amd64" {
-			return baseURL + "/ffmpeg-master-latest-linux64-gpl.zip"
-		}
+func (f *FFmpeg) ConvertWebMToMP4(ctx context.Context, webmPath, mp4Path string, opts EncodeOptions, duration float64, progressCallback func(ConvertProgress)) error {
+	if err := opts.Validate(); err != nil {
+		return err
 	}
 
-	return ""
+	encoder, err := f.resolveEncoder(ctx, opts.codec())
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-i", webmPath}
+	args = append(args, opts.videoArgs(encoder)...)
+	args = append(args,
+		"-c:a", "aac",
+		"-movflags", "+faststart",
+		"-y",
+		mp4Path,
+	)
+
+	return f.convert(ctx, webmPath, mp4Path, args, duration, progressCallback)
 }
 
-func (f *FFmpeg) extractFFmpeg(zipPath, destDir string) error {
-	reader, err := zip.OpenReader(zipPath)
-	if err != nil {
+// convert lance une conversion simple en une passe : vérification de la
+// source, exécution avec progression puis contrôle du fichier produit.
+// duration est la durée attendue de la source (0 si inconnue).
+func (f *FFmpeg) convert(ctx context.Context, inputPath, outputPath string, args []string, duration float64, progressCallback func(ConvertProgress)) error {
+	if err := f.checkSource(inputPath); err != nil {
 		return err
 	}
-	defer reader.Close()
 
-	var binaryName string
-	if runtime.GOOS == "windows" {
-		binaryName = "ffmpeg.exe"
-	} else {
-		binaryName = "ffmpeg"
-	}
-
-	for _, file := range reader.File {
-		if strings.HasSuffix(file.Name, "/bin/"+binaryName) || strings.HasSuffix(file.Name, "\\bin\\"+binaryName) {
-			rc, err := file.Open()
-			if err != nil {
-				return err
-			}
-
-			destPath := filepath.Join(destDir, binaryName)
-			destFile, err := os.Create(destPath)
-			if err != nil {
-				rc.Close()
-				return err
-			}
-
-			_, err = io.Copy(destFile, rc)
-			destFile.Close()
-			rc.Close()
-
-			if err != nil {
-				return err
-			}
-
-			if runtime.GOOS != "windows" {
-				err = os.Chmod(destPath, 0755)
-				if err != nil {
-					return err
-				}
-			}
-
-			return nil
-		}
+	duration = f.expectedDuration(ctx, inputPath, duration)
+	if err := f.runWithProgress(ctx, args, duration, progressCallback); err != nil {
+		os.Remove(outputPath)
+		return err
 	}
 
-	return fmt.Errorf("binaire ffmpeg non trouvé dans l'archive")
+	return checkOutput(outputPath)
 }
 
-func (f *FFmpeg) ConvertWebMToMP4(webmPath, mp4Path string) error {
+func (f *FFmpeg) checkSource(inputPath string) error {
 	if !f.IsInstalled() {
 		return fmt.Errorf("ffmpeg non installé")
 	}
 
 	// Vérifier que le fichier source existe et n'est pas vide
-	if stat, err := os.Stat(webmPath); err != nil {
-		return fmt.Errorf("fichier WebM inaccessible: %w", err)
+	if stat, err := os.Stat(inputPath); err != nil {
+		return fmt.Errorf("fichier source inaccessible: %w", err)
 	} else if stat.Size() == 0 {
-		return fmt.Errorf("fichier WebM source vide")
+		return fmt.Errorf("fichier source vide")
 	}
 
-	cmd := exec.Command(f.BinaryPath,
-		"-i", webmPath,
-		"-c:v", "libx264",
-		"-c:a", "aac",
-		"-preset", "medium",
-		"-crf", "20",
-		"-pix_fmt", "yuv420p",
-		"-movflags", "+faststart",
-		"-y",
-		mp4Path,
-	)
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("ffmpeg error: %w - output: %s", err, string(output))
-	}
+	return nil
+}
 
-	// Vérifier que le fichier MP4 a été créé
-	if stat, err := os.Stat(mp4Path); err != nil {
-		return fmt.Errorf("fichier MP4 non créé: %w", err)
+func checkOutput(outputPath string) error {
+	if stat, err := os.Stat(outputPath); err != nil {
+		return fmt.Errorf("fichier %s non créé: %w", filepath.Base(outputPath), err)
 	} else if stat.Size() == 0 {
-		return fmt.Errorf("fichier MP4 créé mais vide")
+		return fmt.Errorf("fichier %s créé mais vide", filepath.Base(outputPath))
 	}
-
 	return nil
 }
 
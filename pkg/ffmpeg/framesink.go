@@ -0,0 +1,171 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+const (
+	FrameFormatRGBA = "rgba"
+	FrameFormatPNG  = "png"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+type FrameSinkOptions struct {
+	Width  int
+	Height int
+	FPS    int
+	Encode EncodeOptions
+}
+
+// FrameSink encode une suite d'images envoyées une à une sur l'entrée
+// standard de ffmpeg. Contrairement à MediaRecorder, la vidéo produite
+// contient exactement les images reçues, quel que soit le temps de rendu.
+type FrameSink struct {
+	ffmpeg     *FFmpeg
+	ctx        context.Context
+	outputPath string
+	opts       FrameSinkOptions
+	encoder    string
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+	format string
+	frames int
+}
+
+func (f *FFmpeg) NewFrameSink(ctx context.Context, outputPath string, opts FrameSinkOptions) (*FrameSink, error) {
+	if !f.IsInstalled() {
+		return nil, fmt.Errorf("ffmpeg non installé")
+	}
+	if opts.Width <= 0 || opts.Height <= 0 || opts.FPS <= 0 {
+		return nil, fmt.Errorf("paramètres de rendu invalides: %dx%d à %d fps", opts.Width, opts.Height, opts.FPS)
+	}
+	if err := opts.Encode.Validate(); err != nil {
+		return nil, err
+	}
+
+	encoder, err := f.resolveEncoder(ctx, opts.Encode.codec())
+	if err != nil {
+		return nil, err
+	}
+
+	return &FrameSink{
+		ffmpeg:     f,
+		ctx:        ctx,
+		outputPath: outputPath,
+		opts:       opts,
+		encoder:    encoder,
+	}, nil
+}
+
+func (s *FrameSink) Frames() int {
+	return s.frames
+}
+
+// start lance ffmpeg au premier frame : le format d'entrée (PNG ou RGBA brut)
+// est déduit de son contenu.
+func (s *FrameSink) start(format string) error {
+	var args []string
+	switch format {
+	case FrameFormatPNG:
+		args = []string{
+			"-f", "image2pipe",
+			"-c:v", "png",
+			"-framerate", strconv.Itoa(s.opts.FPS),
+		}
+	case FrameFormatRGBA:
+		args = []string{
+			"-f", "rawvideo",
+			"-pix_fmt", "rgba",
+			"-s", fmt.Sprintf("%dx%d", s.opts.Width, s.opts.Height),
+			"-framerate", strconv.Itoa(s.opts.FPS),
+		}
+	}
+	args = append([]string{"-hide_banner", "-nostats"}, args...)
+	args = append(args, "-i", "pipe:0")
+	args = append(args, s.opts.Encode.videoArgs(s.encoder)...)
+	args = append(args, "-movflags", "+faststart", "-y", s.outputPath)
+
+	s.cmd = exec.CommandContext(s.ctx, s.ffmpeg.binary(), args...)
+	s.cmd.Stderr = &s.stderr
+
+	stdin, err := s.cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("erreur pipe ffmpeg: %w", err)
+	}
+	if err := s.cmd.Start(); err != nil {
+		return fmt.Errorf("erreur démarrage ffmpeg: %w", err)
+	}
+
+	s.stdin = stdin
+	s.format = format
+	return nil
+}
+
+func (s *FrameSink) WriteFrame(data []byte) error {
+	format := FrameFormatRGBA
+	if bytes.HasPrefix(data, pngSignature) {
+		format = FrameFormatPNG
+	}
+
+	if format == FrameFormatRGBA {
+		expected := s.opts.Width * s.opts.Height * 4
+		if len(data) != expected {
+			return fmt.Errorf("frame RGBA invalide: %d octets au lieu de %d", len(data), expected)
+		}
+	}
+
+	if s.cmd == nil {
+		if err := s.start(format); err != nil {
+			return err
+		}
+	} else if format != s.format {
+		return fmt.Errorf("format de frame incohérent: %s après %s", format, s.format)
+	}
+
+	if _, err := s.stdin.Write(data); err != nil {
+		if s.ctx.Err() != nil {
+			return fmt.Errorf("rendu annulé: %w", s.ctx.Err())
+		}
+		return fmt.Errorf("erreur écriture frame %d: %w", s.frames, err)
+	}
+
+	s.frames++
+	return nil
+}
+
+// Close termine l'encodage et vérifie le fichier produit.
+func (s *FrameSink) Close() error {
+	if s.cmd == nil {
+		return fmt.Errorf("aucune frame reçue")
+	}
+
+	s.stdin.Close()
+	if err := s.cmd.Wait(); err != nil {
+		os.Remove(s.outputPath)
+		if s.ctx.Err() != nil {
+			return fmt.Errorf("rendu annulé: %w", s.ctx.Err())
+		}
+		return fmt.Errorf("ffmpeg error: %w - output: %s", err, s.stderr.String())
+	}
+
+	return checkOutput(s.outputPath)
+}
+
+// Abort interrompt ffmpeg et supprime la sortie partielle.
+func (s *FrameSink) Abort() {
+	if s.cmd != nil {
+		s.stdin.Close()
+		s.cmd.Process.Kill()
+		s.cmd.Wait()
+	}
+	os.Remove(s.outputPath)
+}
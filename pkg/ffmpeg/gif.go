@@ -0,0 +1,96 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+var gifDitherModes = []string{"sierra2_4a", "floyd_steinberg", "bayer", "sierra2", "heckbert", "none"}
+
+type GIFOptions struct {
+	Width  int    `json:"width"`
+	FPS    int    `json:"fps"`
+	Dither string `json:"dither"`
+	// Loop suit la convention ffmpeg : 0 boucle à l'infini, -1 joue une seule
+	// fois, n répète n fois supplémentaires.
+	Loop int `json:"loop"`
+}
+
+func DefaultGIFOptions() GIFOptions {
+	return GIFOptions{
+		Width:  480,
+		FPS:    15,
+		Dither: "sierra2_4a",
+		Loop:   0,
+	}
+}
+
+func (o GIFOptions) Validate() error {
+	if o.Width < 0 || o.FPS < 0 {
+		return fmt.Errorf("dimensions GIF invalides: largeur %d, fps %d", o.Width, o.FPS)
+	}
+	if o.Loop < -1 {
+		return fmt.Errorf("nombre de boucles invalide: %d", o.Loop)
+	}
+	if o.Dither != "" {
+		for _, mode := range gifDitherModes {
+			if o.Dither == mode {
+				return nil
+			}
+		}
+		return fmt.Errorf("mode de tramage inconnu: %s", o.Dither)
+	}
+	return nil
+}
+
+// ConvertToGIF produit un GIF optimisé en deux passes : palettegen calcule
+// une palette de 256 couleurs adaptée à la vidéo, puis paletteuse l'applique.
+func (f *FFmpeg) ConvertToGIF(ctx context.Context, inputPath, gifPath string, opts GIFOptions, duration float64, progressCallback func(ConvertProgress)) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if err := f.checkSource(inputPath); err != nil {
+		return err
+	}
+
+	palettePath := gifPath + ".palette.png"
+	defer os.Remove(palettePath)
+
+	// La palette doit être calculée sur exactement les mêmes images que la
+	// seconde passe : les deux utilisent la même chaîne fps/scale.
+	baseFilter := scaleFilter(opts.FPS, opts.Width)
+
+	err := f.runWithProgress(ctx, []string{
+		"-i", inputPath,
+		"-vf", baseFilter + ",palettegen=stats_mode=diff",
+		"-y",
+		palettePath,
+	}, 0, nil)
+	if err != nil {
+		return fmt.Errorf("erreur génération palette: %w", err)
+	}
+
+	paletteUse := "paletteuse"
+	if opts.Dither != "" {
+		paletteUse += "=dither=" + opts.Dither
+	}
+
+	args := []string{
+		"-i", inputPath,
+		"-i", palettePath,
+		"-lavfi", baseFilter + " [x]; [x][1:v] " + paletteUse,
+		"-loop", strconv.Itoa(opts.Loop),
+		"-y",
+		gifPath,
+	}
+
+	duration = f.expectedDuration(ctx, inputPath, duration)
+	if err := f.runWithProgress(ctx, args, duration, progressCallback); err != nil {
+		os.Remove(gifPath)
+		return err
+	}
+
+	return checkOutput(gifPath)
+}
@@ -0,0 +1,194 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	SourceCustom  = "custom"
+	SourceManaged = "managed"
+	SourceSystem  = "system"
+)
+
+type Info struct {
+	Path     string   `json:"path"`
+	Source   string   `json:"source"`
+	Version  string   `json:"version"`
+	Encoders []string `json:"encoders"`
+	// Warning signale une copie téléchargée écartée au profit d'un autre
+	// binaire (hash modifié depuis l'installation) ou utilisée sans avoir pu
+	// être vérifiée
+	Warning string `json:"warning,omitempty"`
+}
+
+// SetCustomPath force l'utilisation d'un binaire précis. Une chaîne vide
+// revient à la découverte automatique.
+func (f *FFmpeg) SetCustomPath(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.customPath = path
+	f.binaryPath = ""
+	f.info = nil
+}
+
+// resolveBinary cherche ffmpeg dans l'ordre : chemin personnalisé, copie
+// téléchargée dans ~/.vibecraft/ffmpeg, puis PATH. La copie téléchargée est
+// ignorée si son hash ne correspond plus à celui enregistré à l'installation,
+// et utilisée avec un avertissement si elle est trop ancienne pour en avoir.
+// Appelée avec mu verrouillé.
+func (f *FFmpeg) resolveBinary() (string, string) {
+	f.verified, f.managedErr = nil, nil
+
+	if f.customPath != "" {
+		if _, err := os.Stat(f.customPath); err == nil {
+			return f.customPath, SourceCustom
+		}
+	}
+
+	if managedPath := f.getFFmpegPath(); managedPath != "" {
+		if stat, err := os.Stat(managedPath); err == nil {
+			err := verifyInstalled(managedPath)
+			if err == nil || errors.Is(err, errUnverifiedInstall) {
+				if err != nil {
+					f.managedErr = fmt.Errorf("copie téléchargée de ffmpeg non vérifiée, retéléchargez-la: %w", err)
+				}
+				f.verified = stat
+				return managedPath, SourceManaged
+			}
+			f.managedErr = fmt.Errorf("copie téléchargée de ffmpeg ignorée, retéléchargez-la: %w", err)
+		}
+	}
+
+	if systemPath, err := exec.LookPath("ffmpeg"); err == nil {
+		return systemPath, SourceSystem
+	}
+
+	return "", ""
+}
+
+func (f *FFmpeg) GetInfo(ctx context.Context) (*Info, error) {
+	path := f.binary()
+
+	f.mu.Lock()
+	cached, source, managedErr := f.info, f.source, f.managedErr
+	f.mu.Unlock()
+	if path == "" {
+		if managedErr != nil {
+			return nil, managedErr
+		}
+		return nil, fmt.Errorf("ffmpeg non installé")
+	}
+	if cached != nil && cached.Path == path {
+		return withWarning(cached, managedErr), nil
+	}
+
+	// ffmpeg est lancé hors du verrou : deux appels simultanés peuvent
+	// l'interroger tous les deux, sans conséquence
+	info, err := probeBinary(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info.Source = source
+
+	f.mu.Lock()
+	if f.binaryPath == path {
+		f.info = info
+	}
+	f.mu.Unlock()
+
+	return withWarning(info, managedErr), nil
+}
+
+// withWarning renvoie une copie de info, le cache restant sans avertissement.
+func withWarning(info *Info, managedErr error) *Info {
+	if managedErr == nil {
+		return info
+	}
+	result := *info
+	result.Warning = managedErr.Error()
+	return &result
+}
+
+func (f *FFmpeg) HasEncoder(ctx context.Context, encoder string) bool {
+	info, err := f.GetInfo(ctx)
+	if err != nil {
+		return false
+	}
+	for _, e := range info.Encoders {
+		if e == encoder {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateBinary vérifie qu'un chemin pointe bien vers un ffmpeg exécutable.
+func ValidateBinary(ctx context.Context, path string) error {
+	_, err := probeBinary(ctx, path)
+	return err
+}
+
+func probeBinary(ctx context.Context, path string) (*Info, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, "-hide_banner", "-version").Output()
+	if err != nil {
+		return nil, fmt.Errorf("binaire ffmpeg invalide (%s): %w", path, err)
+	}
+
+	version := parseVersion(output)
+	if version == "" {
+		return nil, fmt.Errorf("binaire ffmpeg invalide (%s): version introuvable", path)
+	}
+
+	output, err = exec.CommandContext(ctx, path, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture des encodeurs: %w", err)
+	}
+
+	return &Info{
+		Path:     path,
+		Version:  version,
+		Encoders: parseEncoders(output),
+	}, nil
+}
+
+func parseVersion(output []byte) string {
+	line, _, _ := bytes.Cut(output, []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) < 3 || fields[0] != "ffmpeg" || fields[1] != "version" {
+		return ""
+	}
+	return fields[2]
+}
+
+// parseEncoders lit la sortie de "ffmpeg -encoders" : une légende, une ligne
+// de tirets, puis une ligne par encodeur ("V....D libx264 ...").
+func parseEncoders(output []byte) []string {
+	var encoders []string
+	started := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if !started {
+			started = len(fields) == 1 && strings.HasPrefix(fields[0], "---")
+			continue
+		}
+		if len(fields) >= 2 {
+			encoders = append(encoders, fields[1])
+		}
+	}
+
+	return encoders
+}
@@ -0,0 +1,45 @@
+package ffmpeg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"ffmpeg version 6.1.1 Copyright (c) 2000-2023 the FFmpeg developers\nbuilt with gcc 13", "6.1.1"},
+		{"ffmpeg version N-113522-g1a2b3c4-20240101 Copyright (c) 2000-2024\n", "N-113522-g1a2b3c4-20240101"},
+		{"ffprobe version 6.1.1 Copyright (c) 2007-2023\n", ""},
+		{"bash: ffmpeg: command not found\n", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseVersion([]byte(tt.output)); got != tt.want {
+			t.Errorf("parseVersion(%q) = %q ; attendu %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestParseEncoders(t *testing.T) {
+	output := `Encoders:
+ V..... = Video
+ A..... = Audio
+ ------
+ V....D libx264              libx264 H.264 / AVC / MPEG-4 AVC
+ V....D libvpx-vp9           libvpx VP9 (codec vp9)
+ A....D aac                  AAC (Advanced Audio Coding)
+
+`
+	want := []string{"libx264", "libvpx-vp9", "aac"}
+	if got := parseEncoders([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseEncoders = %v ; attendu %v", got, want)
+	}
+
+	if got := parseEncoders([]byte("Encoders:\n V..... = Video\n")); got != nil {
+		t.Errorf("parseEncoders sans séparateur = %v ; attendu aucun encodeur", got)
+	}
+}
@@ -0,0 +1,196 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type LoudnessOptions struct {
+	// TargetLUFS est la sonie intégrée visée (EBU R128 : -23, réseaux sociaux : -14).
+	TargetLUFS float64 `json:"targetLufs"`
+	// TruePeak est le niveau crête vrai maximal, en dBTP.
+	TruePeak float64 `json:"truePeak"`
+	// LRA est la plage de sonie visée, en LU.
+	LRA float64 `json:"lra"`
+}
+
+func DefaultLoudnessOptions() LoudnessOptions {
+	return LoudnessOptions{
+		TargetLUFS: -14,
+		TruePeak:   -1,
+		LRA:        11,
+	}
+}
+
+func (o LoudnessOptions) Validate() error {
+	if o.TargetLUFS < -70 || o.TargetLUFS > -5 {
+		return fmt.Errorf("sonie cible invalide: %.1f LUFS (-70 à -5)", o.TargetLUFS)
+	}
+	if o.TruePeak < -9 || o.TruePeak > 0 {
+		return fmt.Errorf("crête vraie invalide: %.1f dBTP (-9 à 0)", o.TruePeak)
+	}
+	if o.LRA < 1 || o.LRA > 20 {
+		return fmt.Errorf("plage de sonie invalide: %.1f LU (1 à 20)", o.LRA)
+	}
+	return nil
+}
+
+// LoudnessReport regroupe les mesures avant et après normalisation.
+type LoudnessReport struct {
+	InputI            float64         `json:"inputI"`
+	InputTP           float64         `json:"inputTp"`
+	InputLRA          float64         `json:"inputLra"`
+	OutputI           float64         `json:"outputI"`
+	OutputTP          float64         `json:"outputTp"`
+	OutputLRA         float64         `json:"outputLra"`
+	NormalizationType string          `json:"normalizationType"`
+	Target            LoudnessOptions `json:"target"`
+}
+
+// loudnormStats correspond au bloc JSON écrit par loudnorm sur stderr.
+type loudnormStats struct {
+	InputI            string `json:"input_i"`
+	InputTP           string `json:"input_tp"`
+	InputLRA          string `json:"input_lra"`
+	InputThresh       string `json:"input_thresh"`
+	OutputI           string `json:"output_i"`
+	OutputTP          string `json:"output_tp"`
+	OutputLRA         string `json:"output_lra"`
+	NormalizationType string `json:"normalization_type"`
+	TargetOffset      string `json:"target_offset"`
+}
+
+func (o LoudnessOptions) filter() string {
+	return fmt.Sprintf("loudnorm=I=%s:TP=%s:LRA=%s",
+		formatSeconds(o.TargetLUFS), formatSeconds(o.TruePeak), formatSeconds(o.LRA))
+}
+
+func parseLoudnormStats(output string) (*loudnormStats, error) {
+	start := strings.LastIndex(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("mesures loudnorm absentes de la sortie ffmpeg")
+	}
+
+	var stats loudnormStats
+	if err := json.Unmarshal([]byte(output[start:end+1]), &stats); err != nil {
+		return nil, fmt.Errorf("erreur décodage loudnorm: %w", err)
+	}
+	return &stats, nil
+}
+
+// parseLoudness convertit une mesure loudnorm. Une piste silencieuse donne
+// "-inf", que le JSON renvoyé au frontend ne saurait pas représenter.
+func parseLoudness(value string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("mesure loudnorm invalide: %q", value)
+	}
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("piste audio silencieuse, normalisation impossible")
+	}
+	return v, nil
+}
+
+// halfProgress ramène la progression d'une passe à la moitié de la barre.
+func halfProgress(progressCallback func(ConvertProgress), second bool) func(ConvertProgress) {
+	if progressCallback == nil {
+		return nil
+	}
+	return func(p ConvertProgress) {
+		p.Percent /= 2
+		if second {
+			p.Percent += 50
+		} else {
+			p.Done = false
+		}
+		progressCallback(p)
+	}
+}
+
+// NormalizeLoudness applique une normalisation EBU R128 en deux passes : la
+// première mesure la piste audio, la seconde corrige le gain de façon
+// linéaire à partir de ces mesures. La vidéo est copiée sans réencodage.
+func (f *FFmpeg) NormalizeLoudness(ctx context.Context, videoPath, outputPath string, opts LoudnessOptions, progressCallback func(ConvertProgress)) (*LoudnessReport, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := f.checkSource(videoPath); err != nil {
+		return nil, err
+	}
+
+	if info, err := f.Probe(ctx, videoPath); err == nil && info.AudioCodec == "" {
+		return nil, fmt.Errorf("aucune piste audio à normaliser")
+	}
+
+	duration := f.videoDuration(ctx, videoPath, 0)
+
+	output, err := f.runWithProgressOutput(ctx, []string{
+		"-i", videoPath,
+		"-vn",
+		"-af", opts.filter() + ":print_format=json",
+		"-f", "null", "-",
+	}, duration, halfProgress(progressCallback, false))
+	if err != nil {
+		return nil, fmt.Errorf("erreur mesure de sonie: %w", err)
+	}
+
+	measured, err := parseLoudnormStats(output)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LoudnessReport{Target: opts}
+	for _, m := range []struct {
+		value string
+		dest  *float64
+	}{
+		{measured.InputI, &report.InputI},
+		{measured.InputTP, &report.InputTP},
+		{measured.InputLRA, &report.InputLRA},
+	} {
+		if *m.dest, err = parseLoudness(m.value); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := parseLoudness(measured.InputThresh); err != nil {
+		return nil, err
+	}
+
+	// loudnorm suréchantillonne à 192 kHz : aresample ramène un débit standard
+	filter := fmt.Sprintf("%s:measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true:print_format=json,aresample=48000",
+		opts.filter(), measured.InputI, measured.InputTP, measured.InputLRA, measured.InputThresh, measured.TargetOffset)
+
+	args := []string{
+		"-i", videoPath,
+		"-af", filter,
+		"-c:v", "copy",
+		"-c:a", audioCodecFor(outputPath),
+	}
+	args = append(args, containerArgs(outputPath)...)
+	args = append(args, "-y", outputPath)
+
+	output, err = f.runWithProgressOutput(ctx, args, duration, halfProgress(progressCallback, true))
+	if err != nil {
+		os.Remove(outputPath)
+		return nil, err
+	}
+	if err := checkOutput(outputPath); err != nil {
+		return nil, err
+	}
+
+	// Les mesures de sortie sont indicatives : leur absence n'invalide pas l'export
+	if normalized, err := parseLoudnormStats(output); err == nil {
+		report.OutputI, _ = parseLoudness(normalized.OutputI)
+		report.OutputTP, _ = parseLoudness(normalized.OutputTP)
+		report.OutputLRA, _ = parseLoudness(normalized.OutputLRA)
+		report.NormalizationType = normalized.NormalizationType
+	}
+
+	return report, nil
+}
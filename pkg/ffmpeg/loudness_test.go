@@ -0,0 +1,54 @@
+package ffmpeg
+
+import "testing"
+
+func TestParseLoudnormStats(t *testing.T) {
+	output := `[Parsed_loudnorm_0 @ 0x55d0c8a1e2c0] 
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"output_tp" : "-1.50",
+	"output_lra" : "14.78",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.58"
+}
+[out#0/null @ 0x55d0c8a1f000] video:0kB audio:1kB`
+
+	stats, err := parseLoudnormStats(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.InputI != "-27.61" || stats.InputThresh != "-39.20" || stats.TargetOffset != "0.58" || stats.NormalizationType != "dynamic" {
+		t.Errorf("parseLoudnormStats = %+v", stats)
+	}
+
+	for _, output := range []string{"", "Conversion failed!", "{ \"input_i\" : -27.61 }", "} {"} {
+		if _, err := parseLoudnormStats(output); err == nil {
+			t.Errorf("parseLoudnormStats(%q) : erreur attendue", output)
+		}
+	}
+}
+
+func TestParseLoudness(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"-27.61", -27.61, true},
+		{" -1.50 ", -1.5, true},
+		{"-inf", 0, false},
+		{"nan", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseLoudness(tt.value)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseLoudness(%q) = %v, %v", tt.value, got, err)
+		}
+	}
+}
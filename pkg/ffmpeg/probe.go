@@ -0,0 +1,150 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type MediaInfo struct {
+	Duration    float64 `json:"duration"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	FrameRate   float64 `json:"frameRate"`
+	Codec       string  `json:"codec"`
+	PixelFormat string  `json:"pixelFormat"`
+	AudioCodec  string  `json:"audioCodec"`
+	Bitrate     int64   `json:"bitrate"`
+	Size        int64   `json:"size"`
+}
+
+type probeOutput struct {
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		PixFmt       string `json:"pix_fmt"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		RFrameRate   string `json:"r_frame_rate"`
+		Duration     string `json:"duration"`
+		BitRate      string `json:"bit_rate"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+		Size     string `json:"size"`
+		BitRate  string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// getFFprobePath cherche ffprobe à côté du ffmpeg utilisé, puis dans le PATH.
+func (f *FFmpeg) getFFprobePath() (string, error) {
+	if binaryPath := f.binary(); binaryPath != "" {
+		sibling := filepath.Join(filepath.Dir(binaryPath), executableName("ffprobe"))
+		if _, err := os.Stat(sibling); err == nil {
+			return sibling, nil
+		}
+	}
+
+	if systemPath, err := exec.LookPath("ffprobe"); err == nil {
+		return systemPath, nil
+	}
+
+	return "", fmt.Errorf("ffprobe introuvable, réinstallez FFmpeg")
+}
+
+func (f *FFmpeg) Probe(ctx context.Context, path string) (*MediaInfo, error) {
+	ffprobePath, err := f.getFFprobePath()
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("fichier inaccessible: %w", err)
+	}
+
+	output, err := exec.CommandContext(ctx, ffprobePath,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe error: %w", err)
+	}
+
+	var probe probeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("erreur décodage ffprobe: %w", err)
+	}
+
+	info := &MediaInfo{
+		Duration: parseFloat(probe.Format.Duration),
+		Bitrate:  parseInt(probe.Format.BitRate),
+		Size:     stat.Size(),
+	}
+
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			if info.Codec != "" {
+				continue
+			}
+			info.Codec = stream.CodecName
+			info.Width = stream.Width
+			info.Height = stream.Height
+			info.PixelFormat = stream.PixFmt
+			info.FrameRate = parseRate(stream.AvgFrameRate)
+			if info.FrameRate == 0 {
+				info.FrameRate = parseRate(stream.RFrameRate)
+			}
+			if info.Duration == 0 {
+				info.Duration = parseFloat(stream.Duration)
+			}
+			if info.Bitrate == 0 {
+				info.Bitrate = parseInt(stream.BitRate)
+			}
+		case "audio":
+			if info.AudioCodec == "" {
+				info.AudioCodec = stream.CodecName
+			}
+		}
+	}
+
+	if info.Codec == "" {
+		return nil, fmt.Errorf("aucun flux vidéo dans %s", filepath.Base(path))
+	}
+
+	return info, nil
+}
+
+// parseRate convertit une fréquence ffprobe ("60/1", "30000/1001") en float.
+func parseRate(rate string) float64 {
+	num, den, ok := strings.Cut(rate, "/")
+	if !ok {
+		return parseFloat(rate)
+	}
+	n := parseFloat(num)
+	d := parseFloat(den)
+	if d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+func parseFloat(value string) float64 {
+	v, _ := strconv.ParseFloat(value, 64)
+	return v
+}
+
+func parseInt(value string) int64 {
+	v, _ := strconv.ParseInt(value, 10, 64)
+	return v
+}
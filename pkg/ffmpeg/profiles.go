@@ -0,0 +1,213 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const DefaultProfile = "standard"
+
+const (
+	CodecH264   = "libx264"
+	CodecH265   = "libx265"
+	CodecAV1    = "av1"
+	CodecSVTAV1 = "libsvtav1"
+	CodecAOMAV1 = "libaom-av1"
+)
+
+type EncodeOptions struct {
+	VideoCodec       string `json:"videoCodec"`
+	CRF              int    `json:"crf"`
+	Bitrate          string `json:"bitrate"`
+	Preset           string `json:"preset"`
+	PixelFormat      string `json:"pixelFormat"`
+	KeyframeInterval int    `json:"keyframeInterval"`
+	Tune             string `json:"tune"`
+}
+
+type EncodeProfile struct {
+	Name    string        `json:"name"`
+	Label   string        `json:"label"`
+	Options EncodeOptions `json:"options"`
+}
+
+var profiles = []EncodeProfile{
+	{
+		Name:  "draft",
+		Label: "Brouillon",
+		Options: EncodeOptions{
+			VideoCodec:  CodecH264,
+			CRF:         28,
+			Preset:      "ultrafast",
+			PixelFormat: "yuv420p",
+		},
+	},
+	{
+		Name:  "standard",
+		Label: "Standard",
+		Options: EncodeOptions{
+			VideoCodec:  CodecH264,
+			CRF:         20,
+			Preset:      "medium",
+			PixelFormat: "yuv420p",
+		},
+	},
+	{
+		Name:  "high_quality",
+		Label: "Haute qualité",
+		Options: EncodeOptions{
+			VideoCodec:  CodecH264,
+			CRF:         16,
+			Preset:      "slow",
+			PixelFormat: "yuv420p",
+			Tune:        "animation",
+		},
+	},
+	{
+		Name:  "archive",
+		Label: "Archive",
+		Options: EncodeOptions{
+			VideoCodec:       CodecH264,
+			CRF:              12,
+			Preset:           "veryslow",
+			PixelFormat:      "yuv420p",
+			KeyframeInterval: 60,
+		},
+	},
+	{
+		Name:  "hevc",
+		Label: "HEVC (H.265)",
+		Options: EncodeOptions{
+			VideoCodec:  CodecH265,
+			CRF:         24,
+			Preset:      "medium",
+			PixelFormat: "yuv420p",
+		},
+	},
+	{
+		Name:  "av1",
+		Label: "AV1",
+		Options: EncodeOptions{
+			VideoCodec:       CodecAV1,
+			CRF:              32,
+			Preset:           "8",
+			PixelFormat:      "yuv420p",
+			KeyframeInterval: 240,
+		},
+	},
+}
+
+func Profiles() []EncodeProfile {
+	out := make([]EncodeProfile, len(profiles))
+	copy(out, profiles)
+	return out
+}
+
+func GetProfile(name string) (EncodeOptions, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return p.Options, nil
+		}
+	}
+	return EncodeOptions{}, fmt.Errorf("profil d'encodage inconnu: %s", name)
+}
+
+func (o EncodeOptions) Validate() error {
+	if o.CRF != 0 && o.Bitrate != "" {
+		return fmt.Errorf("CRF et bitrate ne peuvent pas être utilisés ensemble")
+	}
+	maxCRF := 51
+	if isAV1(o.VideoCodec) {
+		maxCRF = 63
+	}
+	if o.CRF < 0 || o.CRF > maxCRF {
+		return fmt.Errorf("CRF invalide pour %s: %d (0-%d)", o.codec(), o.CRF, maxCRF)
+	}
+	if o.KeyframeInterval < 0 {
+		return fmt.Errorf("intervalle d'images clés invalide: %d", o.KeyframeInterval)
+	}
+	return nil
+}
+
+func (o EncodeOptions) codec() string {
+	if o.VideoCodec == "" {
+		return CodecH264
+	}
+	return o.VideoCodec
+}
+
+func isAV1(codec string) bool {
+	return codec == CodecAV1 || codec == CodecSVTAV1 || codec == CodecAOMAV1
+}
+
+// resolveEncoder vérifie que le binaire ffmpeg dispose de l'encodeur demandé.
+// "av1" choisit libsvtav1, nettement plus rapide, et se rabat sur libaom-av1.
+func (f *FFmpeg) resolveEncoder(ctx context.Context, codec string) (string, error) {
+	info, err := f.GetInfo(ctx)
+	if err != nil {
+		// Impossible de lister les encodeurs : on laisse ffmpeg trancher.
+		if codec == CodecAV1 {
+			return CodecSVTAV1, nil
+		}
+		return codec, nil
+	}
+
+	candidates := []string{codec}
+	if codec == CodecAV1 {
+		candidates = []string{CodecSVTAV1, CodecAOMAV1}
+	}
+
+	for _, candidate := range candidates {
+		for _, encoder := range info.Encoders {
+			if encoder == candidate {
+				return candidate, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("encodeur %s indisponible dans ffmpeg %s (%s): installez une version de FFmpeg qui le prend en charge",
+		strings.Join(candidates, "/"), info.Version, info.Path)
+}
+
+// videoArgs traduit les options en arguments ffmpeg pour l'encodeur donné.
+// Les champs vides laissent ffmpeg choisir sa valeur par défaut.
+func (o EncodeOptions) videoArgs(encoder string) []string {
+	args := []string{"-c:v", encoder}
+
+	if o.Preset != "" {
+		if encoder == CodecAOMAV1 {
+			args = append(args, "-cpu-used", o.Preset)
+		} else {
+			args = append(args, "-preset", o.Preset)
+		}
+	}
+	if o.Bitrate != "" {
+		args = append(args, "-b:v", o.Bitrate)
+	} else if o.CRF > 0 {
+		args = append(args, "-crf", strconv.Itoa(o.CRF))
+		if encoder == CodecAOMAV1 {
+			// libaom n'active le mode qualité constante qu'avec -b:v 0
+			args = append(args, "-b:v", "0")
+		}
+	}
+	if o.Tune != "" {
+		args = append(args, "-tune", o.Tune)
+	}
+	if o.KeyframeInterval > 0 {
+		args = append(args, "-g", strconv.Itoa(o.KeyframeInterval))
+	}
+	if o.PixelFormat != "" {
+		args = append(args, "-pix_fmt", o.PixelFormat)
+	}
+	if encoder == CodecH265 {
+		// Sans le tag hvc1, QuickTime et les lecteurs Apple refusent le HEVC
+		args = append(args, "-tag:v", "hvc1")
+	}
+
+	return args
+}
@@ -0,0 +1,135 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ConvertProgress struct {
+	Percent  float64 `json:"percent"`
+	OutTime  float64 `json:"outTime"`
+	Duration float64 `json:"duration"`
+	FPS      float64 `json:"fps"`
+	Speed    float64 `json:"speed"`
+	ETA      float64 `json:"eta"`
+	Done     bool    `json:"done"`
+}
+
+var durationRegex = regexp.MustCompile(`Duration:\s*(\d+):(\d+):(\d+(?:\.\d+)?)`)
+
+// mediaDuration lit la durée annoncée par ffmpeg pour un fichier, en secondes.
+// Les WebM produits par MediaRecorder n'en déclarent souvent pas : on renvoie
+// alors 0 et la progression ne sera exprimée qu'en temps encodé.
+func (f *FFmpeg) mediaDuration(ctx context.Context, path string) float64 {
+	cmd := exec.CommandContext(ctx, f.binary(), "-hide_banner", "-i", path)
+	output, _ := cmd.CombinedOutput()
+
+	matches := durationRegex.FindSubmatch(output)
+	if len(matches) < 4 {
+		return 0
+	}
+	return parseClock(string(matches[1]), string(matches[2]), string(matches[3]))
+}
+
+// expectedDuration renvoie la durée fournie par l'appelant, qui connaît celle
+// de l'enregistrement, ou à défaut celle annoncée par le fichier.
+func (f *FFmpeg) expectedDuration(ctx context.Context, path string, duration float64) float64 {
+	if duration > 0 {
+		return duration
+	}
+	return f.mediaDuration(ctx, path)
+}
+
+func parseClock(h, m, s string) float64 {
+	hours, _ := strconv.ParseFloat(h, 64)
+	minutes, _ := strconv.ParseFloat(m, 64)
+	seconds, _ := strconv.ParseFloat(s, 64)
+	return hours*3600 + minutes*60 + seconds
+}
+
+// runWithProgress exécute ffmpeg avec -progress sur stdout et remonte
+// l'avancement à chaque bloc. stderr est conservé pour les messages d'erreur.
+// L'annulation du contexte tue le processus ffmpeg.
+func (f *FFmpeg) runWithProgress(ctx context.Context, args []string, duration float64, progressCallback func(ConvertProgress)) error {
+	_, err := f.runWithProgressOutput(ctx, args, duration, progressCallback)
+	return err
+}
+
+// runWithProgressOutput fonctionne comme runWithProgress mais renvoie aussi
+// stderr, où certains filtres (loudnorm) écrivent leurs mesures.
+func (f *FFmpeg) runWithProgressOutput(ctx context.Context, args []string, duration float64, progressCallback func(ConvertProgress)) (string, error) {
+	fullArgs := append([]string{"-hide_banner", "-nostats", "-progress", "pipe:1"}, args...)
+	cmd := exec.CommandContext(ctx, f.binary(), fullArgs...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("erreur pipe ffmpeg: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("erreur démarrage ffmpeg: %w", err)
+	}
+
+	parseProgress(stdout, duration, progressCallback)
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("conversion annulée: %w", ctx.Err())
+		}
+		return "", fmt.Errorf("ffmpeg error: %w - output: %s", err, stderr.String())
+	}
+
+	return stderr.String(), nil
+}
+
+func parseProgress(r io.Reader, duration float64, progressCallback func(ConvertProgress)) {
+	progress := ConvertProgress{Duration: duration}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "out_time_us":
+			if us, err := strconv.ParseFloat(value, 64); err == nil && us >= 0 {
+				progress.OutTime = us / 1e6
+			}
+		case "fps":
+			if fps, err := strconv.ParseFloat(value, 64); err == nil {
+				progress.FPS = fps
+			}
+		case "speed":
+			if speed, err := strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64); err == nil {
+				progress.Speed = speed
+			}
+		case "progress":
+			progress.Done = value == "end"
+			if duration > 0 {
+				progress.Percent = min(progress.OutTime/duration*100, 100)
+				if progress.Speed > 0 {
+					progress.ETA = max((duration-progress.OutTime)/progress.Speed, 0)
+				}
+			}
+			if progress.Done {
+				progress.Percent = 100
+				progress.ETA = 0
+			}
+			if progressCallback != nil {
+				progressCallback(progress)
+			}
+		}
+	}
+}
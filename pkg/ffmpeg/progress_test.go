@@ -0,0 +1,101 @@
+package ffmpeg
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const progressBlocks = `frame=30
+fps=60.0
+out_time_us=1000000
+speed=2.0x
+progress=continue
+frame=60
+fps=60.0
+out_time_us=3000000
+speed=2.0x
+progress=continue
+frame=120
+fps=60.0
+out_time_us=4000000
+speed=2.0x
+progress=end
+`
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration float64
+		want     []ConvertProgress
+	}{
+		{
+			name:     "durée connue",
+			duration: 4,
+			want: []ConvertProgress{
+				{Percent: 25, OutTime: 1, Duration: 4, FPS: 60, Speed: 2, ETA: 1.5},
+				{Percent: 75, OutTime: 3, Duration: 4, FPS: 60, Speed: 2, ETA: 0.5},
+				{Percent: 100, OutTime: 4, Duration: 4, FPS: 60, Speed: 2, Done: true},
+			},
+		},
+		{
+			// Sans durée, seul le temps encodé progresse
+			name:     "durée inconnue",
+			duration: 0,
+			want: []ConvertProgress{
+				{OutTime: 1, FPS: 60, Speed: 2},
+				{OutTime: 3, FPS: 60, Speed: 2},
+				{Percent: 100, OutTime: 4, FPS: 60, Speed: 2, Done: true},
+			},
+		},
+		{
+			// La sortie dépasse légèrement la durée annoncée
+			name:     "durée sous-estimée",
+			duration: 2,
+			want: []ConvertProgress{
+				{Percent: 50, OutTime: 1, Duration: 2, FPS: 60, Speed: 2, ETA: 0.5},
+				{Percent: 100, OutTime: 3, Duration: 2, FPS: 60, Speed: 2},
+				{Percent: 100, OutTime: 4, Duration: 2, FPS: 60, Speed: 2, Done: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var got []ConvertProgress
+		parseProgress(strings.NewReader(progressBlocks), tt.duration, func(p ConvertProgress) {
+			got = append(got, p)
+		})
+		if len(got) != len(tt.want) {
+			t.Errorf("%s : %d mises à jour ; attendu %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s : mise à jour %d = %+v ; attendu %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseProgressIgnoresInvalidValues(t *testing.T) {
+	input := "out_time_us=N/A\nspeed=N/A\nfps=abc\nligne sans égal\nprogress=continue\n"
+
+	var got []ConvertProgress
+	parseProgress(strings.NewReader(input), 10, func(p ConvertProgress) {
+		got = append(got, p)
+	})
+	if len(got) != 1 || got[0] != (ConvertProgress{Duration: 10}) {
+		t.Errorf("parseProgress = %+v", got)
+	}
+}
+
+func TestParseProgressWithoutCallback(t *testing.T) {
+	parseProgress(strings.NewReader(progressBlocks), 4, nil)
+}
+
+func TestExpectedDurationPrefersCaller(t *testing.T) {
+	f := NewFFmpeg()
+	if got := f.expectedDuration(context.Background(), "absent.webm", 12.5); got != 12.5 {
+		t.Errorf("expectedDuration = %v ; attendu 12.5", got)
+	}
+}
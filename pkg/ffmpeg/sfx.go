@@ -0,0 +1,148 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+type SoundEvent struct {
+	Name string  `json:"name"`
+	Time float64 `json:"time"`
+}
+
+type SoundEffect struct {
+	Path   string  `json:"path"`
+	Volume float64 `json:"volume"`
+}
+
+// sfxFilter construit le graphe de mixage : chaque échantillon est dupliqué
+// (asplit) autant de fois qu'il est déclenché, chaque copie est retardée
+// (adelay) à l'instant de l'événement, puis tout est mixé (amix) avec
+// l'éventuelle piste audio déjà présente dans la vidéo.
+func sfxFilter(events []SoundEvent, inputs map[string]int, effects map[string]SoundEffect, keepOriginal bool) string {
+	byName := make(map[string][]SoundEvent)
+	for _, event := range events {
+		byName[event.Name] = append(byName[event.Name], event)
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var chains []string
+	var mixInputs []string
+	if keepOriginal {
+		mixInputs = append(mixInputs, "[0:a]")
+	}
+
+	for _, name := range names {
+		occurrences := byName[name]
+		input := inputs[name]
+
+		split := fmt.Sprintf("[%d:a]asplit=%d", input, len(occurrences))
+		for i := range occurrences {
+			split += fmt.Sprintf("[s%d_%d]", input, i)
+		}
+		chains = append(chains, split)
+
+		volume := effects[name].Volume
+		if volume == 0 {
+			volume = 1
+		}
+
+		for i, event := range occurrences {
+			label := fmt.Sprintf("[e%d_%d]", input, i)
+			chains = append(chains, fmt.Sprintf("[s%d_%d]adelay=%d:all=1,volume=%s%s",
+				input, i, int(event.Time*1000), formatSeconds(volume), label))
+			mixInputs = append(mixInputs, label)
+		}
+	}
+
+	// normalize=0 évite que amix divise le volume par le nombre d'entrées
+	chains = append(chains, fmt.Sprintf("%samix=inputs=%d:duration=longest:normalize=0,apad[aout]",
+		strings.Join(mixInputs, ""), len(mixInputs)))
+
+	return strings.Join(chains, ";\n")
+}
+
+// MixSoundEffects place un échantillon sonore à chaque événement émis par le
+// générateur et mixe le résultat dans la vidéo. Une piste audio existante
+// (musique de fond) est conservée dans le mixage. Les événements postérieurs
+// à la durée de la vidéo (fournie par l'appelant ou lue dans le fichier)
+// sont ignorés.
+func (f *FFmpeg) MixSoundEffects(ctx context.Context, videoPath, outputPath string, events []SoundEvent, effects map[string]SoundEffect, duration float64, progressCallback func(ConvertProgress)) error {
+	if err := f.checkSource(videoPath); err != nil {
+		return err
+	}
+
+	duration = f.videoDuration(ctx, videoPath, duration)
+
+	var kept []SoundEvent
+	for _, event := range events {
+		if _, ok := effects[event.Name]; !ok || event.Time < 0 {
+			continue
+		}
+		if duration > 0 && event.Time >= duration {
+			continue
+		}
+		kept = append(kept, event)
+	}
+	if len(kept) == 0 {
+		return fmt.Errorf("aucun événement sonore à mixer")
+	}
+
+	args := []string{"-i", videoPath}
+	inputs := make(map[string]int)
+	names := make([]string, 0, len(effects))
+	for name := range effects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		effect := effects[name]
+		if _, err := os.Stat(effect.Path); err != nil {
+			return fmt.Errorf("échantillon %s inaccessible: %w", name, err)
+		}
+		inputs[name] = len(inputs) + 1
+		args = append(args, "-i", effect.Path)
+	}
+
+	keepOriginal := false
+	if info, err := f.Probe(ctx, videoPath); err == nil {
+		keepOriginal = info.AudioCodec != ""
+	}
+
+	// Le graphe peut compter des centaines d'entrées : il passe par un fichier
+	// pour ne pas dépasser la longueur maximale d'une ligne de commande.
+	scriptPath := outputPath + ".filter.txt"
+	if err := os.WriteFile(scriptPath, []byte(sfxFilter(kept, inputs, effects, keepOriginal)), 0644); err != nil {
+		return fmt.Errorf("erreur écriture du graphe audio: %w", err)
+	}
+	defer os.Remove(scriptPath)
+
+	args = append(args,
+		"-filter_complex_script", scriptPath,
+		"-map", "0:v",
+		"-map", "[aout]",
+		"-c:v", "copy",
+		"-c:a", audioCodecFor(outputPath),
+		"-shortest",
+	)
+	if duration > 0 {
+		args = append(args, "-t", formatSeconds(duration))
+	}
+	args = append(args, containerArgs(outputPath)...)
+	args = append(args, "-y", outputPath)
+
+	if err := f.runWithProgress(ctx, args, duration, progressCallback); err != nil {
+		os.Remove(outputPath)
+		return err
+	}
+
+	return checkOutput(outputPath)
+}
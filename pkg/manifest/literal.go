@@ -0,0 +1,312 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// literalParser lit un littéral objet JavaScript (clés sans guillemets,
+// chaînes entre apostrophes, virgules finales, commentaires) et le convertit
+// en valeurs Go : map[string]any, []any, string, float64, bool ou nil.
+// Les expressions (variables, appels, calculs) ne sont pas évaluées et
+// provoquent une erreur.
+type literalParser struct {
+	src string
+	pos int
+}
+
+func parseLiteral(src string, start int) (any, int, error) {
+	p := &literalParser{src: src, pos: start}
+	value, err := p.value()
+	if err != nil {
+		return nil, p.pos, err
+	}
+	return value, p.pos, nil
+}
+
+func (p *literalParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:min(p.pos, len(p.src))], "\n") + 1
+	return fmt.Errorf("ligne %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *literalParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch {
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += end + 1
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += end + 4
+		default:
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			if !unicode.IsSpace(r) {
+				return
+			}
+			p.pos += size
+		}
+	}
+}
+
+func (p *literalParser) value() (any, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == 0:
+		return nil, p.errorf("fin de fichier inattendue")
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"' || c == '\'' || c == '`':
+		return p.string()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	case isIdentStart(rune(c)):
+		start := p.pos
+		ident := p.identifier()
+		switch ident {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null", "undefined":
+			return nil, nil
+		}
+		p.pos = start
+		return nil, p.errorf("valeur non littérale %q (variables et expressions non prises en charge)", ident)
+	default:
+		return nil, p.errorf("caractère inattendu %q", c)
+	}
+}
+
+func (p *literalParser) object() (any, error) {
+	p.pos++ // {
+	obj := make(map[string]any)
+	for {
+		p.skipSpace()
+		if p.peek() == '}' {
+			p.pos++
+			return obj, nil
+		}
+
+		var key string
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			k, err := p.string()
+			if err != nil {
+				return nil, err
+			}
+			key = k.(string)
+		case isIdentStart(rune(c)) || (c >= '0' && c <= '9'):
+			key = p.identifier()
+		default:
+			return nil, p.errorf("clé d'objet invalide")
+		}
+
+		p.skipSpace()
+		if p.peek() != ':' {
+			return nil, p.errorf("':' attendu après la clé %q", key)
+		}
+		p.pos++
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = value
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("',' ou '}' attendu après la clé %q", key)
+		}
+	}
+}
+
+func (p *literalParser) array() (any, error) {
+	p.pos++ // [
+	arr := []any{}
+	for {
+		p.skipSpace()
+		if p.peek() == ']' {
+			p.pos++
+			return arr, nil
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, value)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("',' ou ']' attendu dans le tableau")
+		}
+	}
+}
+
+func (p *literalParser) string() (any, error) {
+	quote := p.src[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\n' && quote != '`':
+			return nil, p.errorf("chaîne non terminée")
+		case c == '$' && quote == '`' && strings.HasPrefix(p.src[p.pos:], "${"):
+			return nil, p.errorf("gabarit avec expression non pris en charge")
+		case c == '\\':
+			if err := p.escape(&b); err != nil {
+				return nil, err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return nil, p.errorf("chaîne non terminée")
+}
+
+func (p *literalParser) escape(b *strings.Builder) error {
+	p.pos++ // \
+	if p.pos >= len(p.src) {
+		return p.errorf("échappement incomplet")
+	}
+	c := p.src[p.pos]
+	p.pos++
+
+	switch c {
+	case 'n':
+		b.WriteByte('\n')
+	case 't':
+		b.WriteByte('\t')
+	case 'r':
+		b.WriteByte('\r')
+	case 'b':
+		b.WriteByte('\b')
+	case 'f':
+		b.WriteByte('\f')
+	case 'v':
+		b.WriteByte('\v')
+	case '0':
+		b.WriteByte(0)
+	case '\n':
+		// continuation de ligne
+	case '\r':
+		if p.peek() == '\n' {
+			p.pos++
+		}
+	case 'x', 'u':
+		var hex string
+		switch {
+		case c == 'x':
+			hex = p.src[p.pos:min(p.pos+2, len(p.src))]
+			p.pos += len(hex)
+		case p.peek() == '{':
+			end := strings.IndexByte(p.src[p.pos:], '}')
+			if end < 0 {
+				return p.errorf("échappement unicode invalide")
+			}
+			hex = p.src[p.pos+1 : p.pos+end]
+			p.pos += end + 1
+		default:
+			hex = p.src[p.pos:min(p.pos+4, len(p.src))]
+			p.pos += len(hex)
+		}
+		code, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return p.errorf("échappement invalide \\%c%s", c, hex)
+		}
+		b.WriteRune(rune(code))
+	default:
+		b.WriteByte(c)
+	}
+	return nil
+}
+
+func (p *literalParser) number() (any, error) {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte("0123456789abcdefABCDEFxXoO._+-", p.src[p.pos]) >= 0 {
+		// Un signe n'est valide qu'en tête ou après un exposant
+		if c := p.src[p.pos]; (c == '+' || c == '-') && p.pos > start {
+			if prev := p.src[p.pos-1]; prev != 'e' && prev != 'E' {
+				break
+			}
+		}
+		p.pos++
+	}
+
+	text := strings.ReplaceAll(p.src[start:p.pos], "_", "")
+	sign := 1.0
+	unsigned := text
+	if strings.HasPrefix(text, "-") {
+		sign, unsigned = -1, text[1:]
+	} else if strings.HasPrefix(text, "+") {
+		unsigned = text[1:]
+	}
+
+	if len(unsigned) > 2 && unsigned[0] == '0' && strings.ContainsRune("xXoObB", rune(unsigned[1])) {
+		n, err := strconv.ParseInt(unsigned, 0, 64)
+		if err != nil {
+			return nil, p.errorf("nombre invalide %q", text)
+		}
+		return sign * float64(n), nil
+	}
+
+	n, err := strconv.ParseFloat(unsigned, 64)
+	if err != nil {
+		if isIdentStart(rune(p.peek())) {
+			// Infinity, NaN ou expression : non représentables en JSON
+			return nil, p.errorf("valeur non littérale après %q", text)
+		}
+		return nil, p.errorf("nombre invalide %q", text)
+	}
+	return sign * n, nil
+}
+
+func (p *literalParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !isIdentStart(r) && !unicode.IsDigit(r) {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos]
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
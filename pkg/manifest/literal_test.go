@@ -0,0 +1,167 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLiteralValues(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{`"texte"`, "texte"},
+		{`'texte'`, "texte"},
+		{"`texte`", "texte"},
+		{`true`, true},
+		{`false`, false},
+		{`null`, nil},
+		{`undefined`, nil},
+		{`42`, 42.0},
+		{`-1.5`, -1.5},
+		{`+3`, 3.0},
+		{`.5`, 0.5},
+		{`1e3`, 1000.0},
+		{`2.5E-1`, 0.25},
+		{`1_000`, 1000.0},
+		{`0x1F`, 31.0},
+		{`-0xff`, -255.0},
+		{`0o17`, 15.0},
+		{`0b101`, 5.0},
+		{`[]`, []any{}},
+		{`{}`, map[string]any{}},
+		{`[1, 'a', [true], {}]`, []any{1.0, "a", []any{true}, map[string]any{}}},
+		{
+			`{ name: 'Spirale', "package_id": "com.exemple.spirale", 'version': '1.0.0', 2: 'deux' }`,
+			map[string]any{"name": "Spirale", "package_id": "com.exemple.spirale", "version": "1.0.0", "2": "deux"},
+		},
+		{`{ $prix: 1, _interne: 2, étiquette: 3 }`, map[string]any{"$prix": 1.0, "_interne": 2.0, "étiquette": 3.0}},
+	}
+
+	for _, tt := range tests {
+		got, _, err := parseLiteral(tt.src, 0)
+		if err != nil {
+			t.Errorf("parseLiteral(%s) : %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseLiteral(%s) = %#v ; attendu %#v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestParseLiteralSyntax(t *testing.T) {
+	src := `{
+		// commentaire de ligne
+		name: 'Particules', /* commentaire
+		sur plusieurs lignes */
+		config: [
+			{ name: 'count', type: 'number', default: 50, min: 10, max: 200, },
+			{ name: 'glow', type: 'boolean', default: false },
+		],
+		sound_events: ['bounce',],
+	}`
+
+	got, _, err := parseLiteral(src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"name": "Particules",
+		"config": []any{
+			map[string]any{"name": "count", "type": "number", "default": 50.0, "min": 10.0, "max": 200.0},
+			map[string]any{"name": "glow", "type": "boolean", "default": false},
+		},
+		"sound_events": []any{"bounce"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLiteral = %#v ; attendu %#v", got, want)
+	}
+}
+
+func TestParseLiteralEscapes(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`'l\'apostrophe'`, "l'apostrophe"},
+		{`"des \"guillemets\""`, `des "guillemets"`},
+		{`'a\nb\tc\rd'`, "a\nb\tc\rd"},
+		{`'\\'`, `\`},
+		{`'\x41'`, "A"},
+		{`'é'`, "é"},
+		{`'\u{1F3AC}'`, "🎬"},
+		{"'sur \\\ndeux lignes'", "sur deux lignes"},
+		{"`sur\ndeux lignes`", "sur\ndeux lignes"},
+		{`'\q'`, "q"},
+	}
+
+	for _, tt := range tests {
+		got, _, err := parseLiteral(tt.src, 0)
+		if err != nil {
+			t.Errorf("parseLiteral(%s) : %v", tt.src, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseLiteral(%s) = %q ; attendu %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestParseLiteralRejectsExpressions(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`{ name: NAME }`, "valeur non littérale"},
+		{`{ name: getName() }`, "valeur non littérale"},
+		{`{ size: Infinity }`, "valeur non littérale"},
+		{`{ size: 1 + 2 }`, "',' ou '}' attendu"},
+		{`[1-2]`, "',' ou ']' attendu"},
+		{`{ size: 10px }`, "',' ou '}' attendu"},
+		{"{ name: `v${version}` }", "gabarit avec expression"},
+		{`{ [key]: 1 }`, "clé d'objet invalide"},
+		{`{ name 'x' }`, "':' attendu"},
+		{`{ name: `, "fin de fichier inattendue"},
+		{`{ name: 'x' `, "',' ou '}' attendu"},
+		{`{ name: 'x`, "chaîne non terminée"},
+		{"{ name: 'x\n' }", "chaîne non terminée"},
+		{`'\u00zz'`, "échappement invalide"},
+		{`'\u{1F3AC'`, "échappement unicode invalide"},
+		{`0x`, "nombre invalide"},
+		{`{ a: 1, }; { b: `, ""},
+	}
+
+	for _, tt := range tests {
+		_, _, err := parseLiteral(tt.src, 0)
+		if tt.want == "" {
+			// Le texte qui suit le littéral n'est pas lu
+			if err != nil {
+				t.Errorf("parseLiteral(%s) : %v", tt.src, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseLiteral(%s) = %v ; attendu une erreur contenant %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestParseLiteralPosition(t *testing.T) {
+	src := "const MANIFEST = { a: [1, 2] };\nclass Spirale {}"
+	start := strings.IndexByte(src, '{')
+
+	_, end, err := parseLiteral(src, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := src[end:]; !strings.HasPrefix(got, ";\nclass") {
+		t.Errorf("fin du littéral à %d (%q)", end, got)
+	}
+
+	_, _, err = parseLiteral("const MANIFEST = {\n  a: 1,\n  b: VALEUR\n};", 17)
+	if err == nil || !strings.HasPrefix(err.Error(), "ligne 3:") {
+		t.Errorf("erreur sans le bon numéro de ligne : %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+type ConfigParam struct {
+	Type     string        `json:"type"`
+	Name     string        `json:"name"`
+	Label    string        `json:"label,omitempty"`
+	Default  any           `json:"default,omitempty"`
+	Min      *float64      `json:"min,omitempty"`
+	Max      *float64      `json:"max,omitempty"`
+	Step     *float64      `json:"step,omitempty"`
+	DependOn string        `json:"depend_on,omitempty"`
+	Collapse bool          `json:"collapse,omitempty"`
+	Content  []ConfigParam `json:"content,omitempty"`
+}
+
+type Manifest struct {
+	Name                  string        `json:"name"`
+	Version               string        `json:"version"`
+	PackageID             string        `json:"package_id"`
+	APIVersion            string        `json:"api_version"`
+	Author                string        `json:"author"`
+	Description           string        `json:"description"`
+	TransparentBackground bool          `json:"transparent_background"`
+	SoundEvents           []string      `json:"sound_events"`
+	Config                []ConfigParam `json:"config"`
+}
+
+var (
+	getterRegex = regexp.MustCompile(`static\s+get\s+manifest\s*\(\s*\)\s*\{\s*return\s+(\{|[A-Za-z_$][\w$]*)`)
+	defaultName = "MANIFEST"
+)
+
+// Extract retrouve le manifest d'un générateur dans son code source sans
+// l'exécuter : soit l'objet renvoyé par `static get manifest()`, soit la
+// constante qu'il renvoie (MANIFEST par convention).
+func Extract(source string) (*Manifest, error) {
+	start := -1
+	name := defaultName
+
+	if m := getterRegex.FindStringSubmatchIndex(source); m != nil {
+		if source[m[2]:m[3]] == "{" {
+			start = m[2]
+		} else {
+			name = source[m[2]:m[3]]
+		}
+	}
+
+	if start < 0 {
+		declRegex := regexp.MustCompile(`(?:const|let|var)\s+` + regexp.QuoteMeta(name) + `\s*=\s*\{`)
+		loc := declRegex.FindStringIndex(source)
+		if loc == nil {
+			return nil, fmt.Errorf("manifest introuvable (constante %s ou static get manifest())", name)
+		}
+		start = loc[1] - 1
+	}
+
+	value, _, err := parseLiteral(source, start)
+	if err != nil {
+		return nil, fmt.Errorf("manifest invalide, %w", err)
+	}
+
+	// Le passage par JSON applique les types attendus à l'objet lu
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("manifest invalide: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("manifest invalide: %w", err)
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return &manifest, err
+	}
+	return &manifest, nil
+}
+
+func (m *Manifest) Validate() error {
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"name", m.Name},
+		{"version", m.Version},
+		{"package_id", m.PackageID},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("champs obligatoires manquants dans le manifest: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// CompareVersions compare deux versions semver ("1.2.0" ou "v1.2.0") et
+// renvoie -1, 0 ou 1 comme strings.Compare.
+func CompareVersions(a, b string) (int, error) {
+	va, vb := normalizeVersion(a), normalizeVersion(b)
+	if !semver.IsValid(va) {
+		return 0, fmt.Errorf("version invalide: %q", a)
+	}
+	if !semver.IsValid(vb) {
+		return 0, fmt.Errorf("version invalide: %q", b)
+	}
+	return semver.Compare(va, vb), nil
+}
+
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return version
+}
+
+// FlattenConfig aplatit les catégories pour ne garder que les paramètres.
+func FlattenConfig(config []ConfigParam) []ConfigParam {
+	var params []ConfigParam
+	for _, param := range config {
+		if param.Type == "categorie" {
+			params = append(params, FlattenConfig(param.Content)...)
+		} else {
+			params = append(params, param)
+		}
+	}
+	return params
+}
@@ -0,0 +1,100 @@
+package safepath
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrEmptyPath     = errors.New("chemin vide")
+	ErrAbsolutePath  = errors.New("chemin absolu interdit")
+	ErrTraversal     = errors.New("remontée de dossier interdite")
+	ErrSymlinkEscape = errors.New("lien symbolique hors du dossier autorisé")
+)
+
+// PathError indique qu'un nom fourni par l'appelant a été refusé. Err vaut
+// l'une des erreurs ErrXxx du paquet, testable avec errors.Is.
+type PathError struct {
+	Root string
+	Name string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%s: %q", e.Err, e.Name)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Resolve joint un chemin relatif fourni par l'appelant au dossier racine en
+// garantissant que le résultat reste dans ce dossier : les chemins absolus,
+// les segments ".." et les liens symboliques qui pointent hors de la racine
+// sont refusés. Le fichier n'a pas besoin d'exister.
+func Resolve(root, name string) (string, error) {
+	fail := func(err error) (string, error) {
+		return "", &PathError{Root: root, Name: name, Err: err}
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return fail(ErrEmptyPath)
+	}
+	// Les deux séparateurs sont refusés quel que soit le système, pour qu'un
+	// nom accepté sous Linux ne devienne pas dangereux sous Windows.
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return fail(ErrAbsolutePath)
+	}
+	if len(name) >= 2 && name[1] == ':' {
+		return fail(ErrAbsolutePath)
+	}
+
+	segments := strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' })
+	for _, segment := range segments {
+		if segment == ".." {
+			return fail(ErrTraversal)
+		}
+	}
+
+	root = filepath.Clean(root)
+	fullPath := filepath.Join(append([]string{root}, segments...)...)
+	if fullPath == root {
+		return fail(ErrEmptyPath)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		// Racine absente : aucun lien ne peut encore s'y trouver
+		return fullPath, nil
+	}
+
+	// Le premier élément existant du chemin, en partant du fichier, est
+	// résolu : s'il mène hors de la racine, un lien a servi d'échappatoire.
+	for p := fullPath; p != root && len(p) > len(root); p = filepath.Dir(p) {
+		if _, err := os.Lstat(p); err != nil {
+			continue
+		}
+		realPath, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			// Lien cassé : sa cible ne peut pas être vérifiée
+			return fail(ErrSymlinkEscape)
+		}
+		if !within(realRoot, realPath) {
+			return fail(ErrSymlinkEscape)
+		}
+		break
+	}
+
+	return fullPath, nil
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
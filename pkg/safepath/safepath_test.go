@@ -0,0 +1,162 @@
+package safepath
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveRejectsUnsafeNames(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"", ErrEmptyPath},
+		{"   ", ErrEmptyPath},
+		{".", ErrEmptyPath},
+		{"./", ErrEmptyPath},
+		{"..", ErrTraversal},
+		{"../secret", ErrTraversal},
+		{"assets/../../secret", ErrTraversal},
+		{"assets/../logo.png", ErrTraversal},
+		{`..\secret`, ErrTraversal},
+		{`assets\..\..\secret`, ErrTraversal},
+		{"/etc/passwd", ErrAbsolutePath},
+		{`\Windows\win.ini`, ErrAbsolutePath},
+		{`\\serveur\partage\fichier`, ErrAbsolutePath},
+		{`C:\Windows\win.ini`, ErrAbsolutePath},
+		{"C:/Windows/win.ini", ErrAbsolutePath},
+		{"c:secret", ErrAbsolutePath},
+	}
+
+	for _, tt := range tests {
+		got, err := Resolve(root, tt.name)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Resolve(%q) = %q, %v ; attendu %v", tt.name, got, err, tt.want)
+			continue
+		}
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) || pathErr.Name != tt.name {
+			t.Errorf("Resolve(%q) : erreur %T sans le nom refusé", tt.name, err)
+		}
+	}
+}
+
+func TestResolveJoinsRelativeNames(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"index.js", "index.js"},
+		{"assets/logo.png", "assets/logo.png"},
+		{`assets\logo.png`, "assets/logo.png"},
+		{"./assets//fonts/police.woff2", "assets/fonts/police.woff2"},
+		{"absent/fichier.txt", "absent/fichier.txt"},
+		{"..logo.png", "..logo.png"},
+	}
+
+	for _, tt := range tests {
+		got, err := Resolve(root, tt.name)
+		if err != nil {
+			t.Errorf("Resolve(%q) : %v", tt.name, err)
+			continue
+		}
+		if want := filepath.Join(root, filepath.FromSlash(tt.want)); got != want {
+			t.Errorf("Resolve(%q) = %q ; attendu %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestResolveMissingRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "absent")
+
+	got, err := Resolve(root, "assets/logo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "assets", "logo.png"); got != want {
+		t.Errorf("Resolve = %q ; attendu %q", got, want)
+	}
+}
+
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("liens symboliques indisponibles: %v", err)
+	}
+}
+
+func TestResolveSymlinks(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(root, "assets"), filepath.Join(outside, "nested")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "assets", "logo.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lien vers un fichier extérieur
+	symlink(t, filepath.Join(outside, "secret.txt"), filepath.Join(root, "secret.txt"))
+	// Dossier intermédiaire qui mène hors de la racine
+	symlink(t, outside, filepath.Join(root, "escape"))
+	// Lien relatif qui remonte au-dessus de la racine
+	symlink(t, filepath.Join("..", "..", "outside"), filepath.Join(root, "assets", "up"))
+	// Liens qui restent dans la racine
+	symlink(t, filepath.Join(root, "assets"), filepath.Join(root, "media"))
+	symlink(t, "logo.png", filepath.Join(root, "assets", "alias.png"))
+	// Lien cassé, dont la cible ne peut pas être vérifiée
+	symlink(t, filepath.Join(base, "missing"), filepath.Join(root, "broken"))
+
+	rejected := []string{
+		"secret.txt",
+		"escape",
+		"escape/secret.txt",
+		"escape/nested/absent.txt",
+		"escape/absent/absent.txt",
+		"assets/up/secret.txt",
+		"broken",
+		"broken/absent.txt",
+	}
+	for _, name := range rejected {
+		if got, err := Resolve(root, name); !errors.Is(err, ErrSymlinkEscape) {
+			t.Errorf("Resolve(%q) = %q, %v ; attendu %v", name, got, err, ErrSymlinkEscape)
+		}
+	}
+
+	accepted := []string{
+		"media/logo.png",
+		"media/absent.png",
+		"assets/alias.png",
+	}
+	for _, name := range accepted {
+		if _, err := Resolve(root, name); err != nil {
+			t.Errorf("Resolve(%q) : %v", name, err)
+		}
+	}
+}
+
+func TestResolveSymlinkedRoot(t *testing.T) {
+	base := t.TempDir()
+	realRoot := filepath.Join(base, "real")
+	if err := os.MkdirAll(filepath.Join(realRoot, "assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(base, "root")
+	symlink(t, realRoot, root)
+
+	if _, err := Resolve(root, "assets/logo.png"); err != nil {
+		t.Errorf("racine atteinte par un lien refusée: %v", err)
+	}
+}
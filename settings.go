@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"VibeCraft/pkg/ffmpeg"
+)
+
+type Settings struct {
+	DefaultProfile string `json:"defaultProfile"`
+	FFmpegPath     string `json:"ffmpegPath"`
+	// OutputDir est le dossier proposé par défaut à l'export.
+	OutputDir string `json:"outputDir"`
+	// FilenameTemplate accepte {generator}, {date} et {resolution}.
+	FilenameTemplate string `json:"filenameTemplate"`
+	// Limites du dossier temporaire appliquées au démarrage, 0 pour désactiver.
+	TempMaxAgeHours int `json:"tempMaxAgeHours"`
+	TempMaxSizeMB   int `json:"tempMaxSizeMB"`
+	// LinkedGenerators sont les dossiers de développement liés.
+	LinkedGenerators []string `json:"linkedGenerators"`
+}
+
+func defaultSettings() Settings {
+	return Settings{
+		DefaultProfile:   ffmpeg.DefaultProfile,
+		FilenameTemplate: defaultFilenameTemplate,
+		TempMaxAgeHours:  defaultTempMaxAgeHours,
+		TempMaxSizeMB:    defaultTempMaxSizeMB,
+	}
+}
+
+func (a *App) getSettingsPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".vibecraft", "settings.json")
+}
+
+func (a *App) GetSettings() Settings {
+	settings := defaultSettings()
+
+	data, err := os.ReadFile(a.getSettingsPath())
+	if err != nil {
+		return settings
+	}
+	json.Unmarshal(data, &settings)
+
+	if _, err := ffmpeg.GetProfile(settings.DefaultProfile); err != nil {
+		settings.DefaultProfile = ffmpeg.DefaultProfile
+	}
+
+	return settings
+}
+
+func (a *App) SaveSettings(settings Settings) error {
+	if _, err := ffmpeg.GetProfile(settings.DefaultProfile); err != nil {
+		return err
+	}
+	// Seul un chemin modifié est vérifié : un binaire supprimé depuis ne doit
+	// pas bloquer l'enregistrement des autres réglages
+	previous := a.GetSettings()
+	ffmpegPathChanged := settings.FFmpegPath != previous.FFmpegPath
+	if settings.FFmpegPath != "" && ffmpegPathChanged {
+		if err := ffmpeg.ValidateBinary(a.ctx, settings.FFmpegPath); err != nil {
+			return err
+		}
+	}
+
+	// Un disque d'export débranché ne bloque pas non plus les autres réglages
+	if settings.OutputDir != "" && settings.OutputDir != previous.OutputDir {
+		if stat, err := os.Stat(settings.OutputDir); err != nil || !stat.IsDir() {
+			return fmt.Errorf("dossier d'export introuvable: %s", settings.OutputDir)
+		}
+	}
+	if err := validateFilenameTemplate(settings.FilenameTemplate); err != nil {
+		return err
+	}
+	if settings.TempMaxAgeHours < 0 || settings.TempMaxSizeMB < 0 {
+		return fmt.Errorf("limites du dossier temporaire invalides")
+	}
+
+	settingsPath := a.getSettingsPath()
+	os.MkdirAll(filepath.Dir(settingsPath), 0755)
+
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(settingsPath, out, 0644); err != nil {
+		return err
+	}
+
+	if ffmpegPathChanged {
+		a.ffmpeg.SetCustomPath(settings.FFmpegPath)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"VibeCraft/pkg/safepath"
+)
+
+// tempURLPrefix est la route de l'AssetServer qui sert les fichiers du
+// dossier temporaire au frontend (lecture en streaming, requêtes Range).
+const tempURLPrefix = "/temp/"
+
+type tempUpload struct {
+	file *os.File
+	size int64
+}
+
+type uploadRegistry struct {
+	mu      sync.Mutex
+	uploads map[string]*tempUpload
+}
+
+func newUploadRegistry() *uploadRegistry {
+	return &uploadRegistry{uploads: make(map[string]*tempUpload)}
+}
+
+func getTempDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".vibecraft", "temp")
+}
+
+// tempFilePath refuse tout nom qui sortirait du dossier temporaire. Ce
+// dossier est plat (le nettoyage ignore les sous-dossiers) : seuls les noms
+// de fichier simples sont acceptés.
+func tempFilePath(filename string) (string, error) {
+	if filepath.Base(filename) != filename || strings.ContainsAny(filename, `/\`) {
+		return "", fmt.Errorf("nom de fichier temporaire invalide: %q", filename)
+	}
+	return safepath.Resolve(getTempDir(), filename)
+}
+
+// BeginTempUpload ouvre un envoi par morceaux : le frontend transmet ensuite
+// le fichier avec AppendTempUpload puis le valide avec CommitTempUpload.
+// Les données sont écrites dans un fichier .part tant que l'envoi n'est pas
+// validé. size permet de vérifier l'espace disque avant d'écrire.
+func (a *App) BeginTempUpload(filename string, size int64) error {
+	filePath, err := tempFilePath(filename)
+	if err != nil {
+		return err
+	}
+
+	a.uploads.mu.Lock()
+	defer a.uploads.mu.Unlock()
+
+	if _, exists := a.uploads.uploads[filename]; exists {
+		return fmt.Errorf("envoi déjà en cours: %s", filename)
+	}
+
+	if err := os.MkdirAll(getTempDir(), 0755); err != nil {
+		return fmt.Errorf("erreur création dossier temporaire: %w", err)
+	}
+	if err := ensureDiskSpace(getTempDir(), size); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filePath + ".part")
+	if err != nil {
+		return fmt.Errorf("erreur création %s: %w", filename, err)
+	}
+
+	a.uploads.uploads[filename] = &tempUpload{file: file}
+	return nil
+}
+
+// AppendTempUpload ajoute un morceau encodé en base64 à un envoi en cours.
+func (a *App) AppendTempUpload(filename string, chunk string) error {
+	a.uploads.mu.Lock()
+	defer a.uploads.mu.Unlock()
+
+	upload, exists := a.uploads.uploads[filename]
+	if !exists {
+		return fmt.Errorf("aucun envoi en cours: %s", filename)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(chunk)
+	if err != nil {
+		a.abortUpload(filename, upload)
+		return fmt.Errorf("morceau invalide pour %s: %w", filename, err)
+	}
+
+	n, err := upload.file.Write(data)
+	upload.size += int64(n)
+	if err != nil {
+		a.abortUpload(filename, upload)
+		return fmt.Errorf("erreur écriture %s: %w", filename, err)
+	}
+	return nil
+}
+
+// CommitTempUpload termine un envoi et rend le fichier visible sous son nom.
+func (a *App) CommitTempUpload(filename string) error {
+	a.uploads.mu.Lock()
+	defer a.uploads.mu.Unlock()
+
+	upload, exists := a.uploads.uploads[filename]
+	if !exists {
+		return fmt.Errorf("aucun envoi en cours: %s", filename)
+	}
+	delete(a.uploads.uploads, filename)
+
+	partPath := upload.file.Name()
+	if err := upload.file.Close(); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("erreur écriture %s: %w", filename, err)
+	}
+	if upload.size == 0 {
+		os.Remove(partPath)
+		return fmt.Errorf("fichier vide: %s", filename)
+	}
+
+	if err := os.Rename(partPath, strings.TrimSuffix(partPath, ".part")); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("erreur finalisation %s: %w", filename, err)
+	}
+	return nil
+}
+
+func (a *App) AbortTempUpload(filename string) error {
+	a.uploads.mu.Lock()
+	defer a.uploads.mu.Unlock()
+
+	upload, exists := a.uploads.uploads[filename]
+	if !exists {
+		return fmt.Errorf("aucun envoi en cours: %s", filename)
+	}
+	a.abortUpload(filename, upload)
+	return nil
+}
+
+func (a *App) abortUpload(filename string, upload *tempUpload) {
+	delete(a.uploads.uploads, filename)
+	upload.file.Close()
+	os.Remove(upload.file.Name())
+}
+
+func (a *App) DeleteTempFile(filename string) error {
+	filePath, err := tempFilePath(filename)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filePath)
+}
+
+// tempFileHandler sert les fichiers du dossier temporaire sous /temp/<nom>.
+// http.ServeContent gère les requêtes Range, ce qui permet au frontend de
+// lire ou télécharger une vidéo sans la faire transiter en JSON.
+func tempFileHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "méthode non autorisée", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filePath, err := tempFilePath(strings.TrimPrefix(r.URL.Path, tempURLPrefix))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	http.ServeContent(w, r, stat.Name(), stat.ModTime(), file)
+}
+
+// assetHandler regroupe les routes dynamiques servies par l'AssetServer de
+// Wails, en plus des fichiers embarqués du frontend.
+func (a *App) assetHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(tempURLPrefix, tempFileHandler)
+	mux.HandleFunc(generatorAssetsURLPrefix, a.generatorAssetsHandler)
+	return mux
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTempFilePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	tempDir := getTempDir()
+
+	accepted := []string{"1700000000_video.mp4", "export.webm.part", "..video.mp4"}
+	for _, name := range accepted {
+		got, err := tempFilePath(name)
+		if err != nil {
+			t.Errorf("tempFilePath(%q) : %v", name, err)
+			continue
+		}
+		if want := filepath.Join(tempDir, name); got != want {
+			t.Errorf("tempFilePath(%q) = %q ; attendu %q", name, got, want)
+		}
+	}
+
+	rejected := []string{
+		"",
+		".",
+		"..",
+		"sub/video.webm",
+		`sub\video.webm`,
+		"../settings.json",
+		`..\settings.json`,
+		"/etc/passwd",
+		`C:\Windows\win.ini`,
+		"C:video.webm",
+	}
+	for _, name := range rejected {
+		if got, err := tempFilePath(name); err == nil {
+			t.Errorf("tempFilePath(%q) = %q ; attendu une erreur", name, got)
+		}
+	}
+}
+
+func TestTempFilePathSymlinkEscape(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.MkdirAll(getTempDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(home, "outside.mp4"), filepath.Join(getTempDir(), "lien.mp4")); err != nil {
+		t.Skipf("liens symboliques indisponibles: %v", err)
+	}
+
+	if got, err := tempFilePath("lien.mp4"); err == nil {
+		t.Errorf("tempFilePath(lien.mp4) = %q ; attendu une erreur", got)
+	}
+}